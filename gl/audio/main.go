@@ -30,10 +30,17 @@ import (
 	"goarrg.com"
 	"goarrg.com/debug"
 	"goarrg.com/examples/gl/shared/gl2d"
+	"goarrg.com/examples/gl/shared/mixer"
 	"goarrg.com/gmath"
 	"goarrg.com/platform/sdl"
 )
 
+// renderer resolution the visualizer is laid out for
+const (
+	resW = 800
+	resH = 600
+)
+
 func main() {
 	// debug.LogSetLevel(debug.LogLevelError)
 	err := sdl.Setup(sdl.Config{
@@ -51,7 +58,13 @@ func main() {
 		os.Exit(1)
 	}
 
-	err = Setup("test.wav")
+	err = gl2d.Setup(gl2d.Config{ResW: resW, ResH: resH})
+	if err != nil {
+		debug.EPrint(err)
+		os.Exit(1)
+	}
+
+	err = mixer.Setup("test.wav")
 	if err != nil {
 		debug.EPrint(err)
 		os.Exit(1)
@@ -59,7 +72,7 @@ func main() {
 
 	err = goarrg.Run(goarrg.Config{
 		Platform: sdl.Platform,
-		Audio:    Mixer,
+		Audio:    mixer.Mixer,
 		Renderer: gl2d.Renderer,
 		Program:  &program{},
 	})
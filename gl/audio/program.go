@@ -20,35 +20,66 @@ limitations under the License.
 package main
 
 import (
+	"math"
 	"os"
 	"time"
 
 	"goarrg.com"
 	"goarrg.com/debug"
+	"goarrg.com/gmath"
+
+	"goarrg.com/examples/gl/shared/gl2d"
+	"goarrg.com/examples/gl/shared/mixer"
 )
 
+// the sound circles the listener at this distance in world units
+const orbitRadius = 300
+
 type program struct {
 	timer *time.Timer
+	// angle of the sound around the listener in radians
+	angle      float64
+	visualizer *visualizer
 }
 
 func (p *program) Init(goarrg.PlatformInterface) error {
+	err := mixer.DefaultSoundBank.Load("test2.wav")
+	if err != nil {
+		return err
+	}
+
+	p.visualizer, err = newVisualizer()
+	if err != nil {
+		return err
+	}
+
 	p.timer = time.NewTimer(time.Millisecond * 500)
 
 	return nil
 }
 
 func (p *program) Update(deltaTime float64) {
+	layer := gl2d.DefaultLayer()
+	center := layer.ViewCenter()
+	mixer.SetListener(mixer.Listener{Pos: center, Rotation: layer.Camera().Rotation})
+
+	p.angle += deltaTime
+	sin, cos := math.Sincos(p.angle)
+
 	select {
 	case <-p.timer.C:
-		err := PlaySound("test2.wav")
+		v, err := mixer.PlaySoundAt("test2.wav", gmath.Point3f64{X: center.X + cos*orbitRadius, Y: center.Y + sin*orbitRadius})
 		if err != nil {
 			debug.EPrint(err)
 			os.Exit(1)
 		}
 
+		p.visualizer.voice = v
 		p.timer.Reset(time.Millisecond * 500)
 	default:
 	}
+
+	p.visualizer.update(deltaTime)
 }
 
 func (p *program) Shutdown() bool {
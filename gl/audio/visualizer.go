@@ -0,0 +1,162 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"math"
+
+	"goarrg.com/gmath"
+
+	"goarrg.com/examples/gl/shared/gl2d"
+	"goarrg.com/examples/gl/shared/mixer"
+)
+
+// layout in renderer resolution units, drawn in the bottom left corner
+const (
+	visualizerMargin = 10
+	meterWidth       = 12
+	meterGap         = 4
+	visualizerHeight = 120
+	spectrumBands    = 48
+	spectrumWidth    = 400
+	// levels at or below this are drawn empty
+	visualizerFloorDB = -60
+	// held peaks fall the full height in this many seconds
+	peakFallSeconds = 1.5
+	// spectrum bands are spaced evenly in octaves between these frequencies
+	spectrumLowHz  = 40
+	spectrumHighHz = 16000
+)
+
+/*
+visualizer draws the meters of the master track, the category buses and the
+last sound played next to the spectrum of the master track on a layer above
+the game so the camera does not move it.
+*/
+type visualizer struct {
+	layer    *gl2d.Layer
+	bar      gl2d.Sprite
+	analyzer *mixer.Analyzer
+	buses    []*mixer.Bus
+	// last sound played, nil before the first one
+	voice *mixer.Voice
+
+	// held peak of every meter as a fraction of the height
+	peaks    []float64
+	spectrum []float32
+	sprites  []gl2d.Sprite
+}
+
+func newVisualizer() (*visualizer, error) {
+	layer, err := gl2d.LayerCreate("visualizer", gl2d.LayerConfig{Order: 1})
+	if err != nil {
+		return nil, err
+	}
+
+	bar, err := gl2d.SpriteLoad("white.png")
+	if err != nil {
+		return nil, err
+	}
+
+	v := &visualizer{
+		layer:    layer,
+		bar:      bar,
+		analyzer: mixer.NewAnalyzer(4096),
+	}
+	for _, c := range []mixer.Category{mixer.CategorySFX, mixer.CategoryMusic, mixer.CategoryUI, mixer.CategoryVoice} {
+		v.buses = append(v.buses, mixer.CategoryBus(c))
+	}
+	mixer.AddMasterEffect(v.analyzer)
+
+	return v, nil
+}
+
+// returns the fraction of the height an amplitude is drawn at
+func levelHeight(amplitude float32) float64 {
+	db := 20 * math.Log10(max(float64(amplitude), 1e-9))
+	return min(max(1-db/visualizerFloorDB, 0), 1)
+}
+
+// returns green for quiet levels through yellow to red at full scale
+func levelColor(height float64) [4]float32 {
+	return [4]float32{float32(min(2*height, 1)), float32(min(2-2*height, 1)), 0.2, 0.9}
+}
+
+func (v *visualizer) update(deltaTime float64) {
+	v.sprites = v.sprites[:0]
+	bottom := float64(resH - visualizerMargin)
+
+	levels := []mixer.Level{mixer.MasterLevel()}
+	for _, b := range v.buses {
+		levels = append(levels, b.Level())
+	}
+	if v.voice != nil {
+		levels = append(levels, v.voice.Level())
+	}
+
+	for len(v.peaks) < len(levels) {
+		v.peaks = append(v.peaks, 0)
+	}
+
+	x := float64(visualizerMargin)
+	for i, l := range levels {
+		rms := levelHeight(l.RMS)
+		v.peaks[i] = max(levelHeight(l.Peak), v.peaks[i]-deltaTime/peakFallSeconds)
+
+		v.rect(x, bottom-rms*visualizerHeight, meterWidth, rms*visualizerHeight, levelColor(rms))
+		v.rect(x, bottom-v.peaks[i]*visualizerHeight, meterWidth, 2, [4]float32{1, 1, 1, 1})
+		x += meterWidth + meterGap
+	}
+
+	x += visualizerMargin
+	v.spectrum = v.analyzer.Spectrum(v.spectrum[:0])
+	width := float64(spectrumWidth) / spectrumBands
+	octaves := math.Log2(spectrumHighHz / spectrumLowHz)
+	binHz := v.analyzer.BinFrequency(1)
+
+	for band := 0; band < spectrumBands; band++ {
+		if binHz == 0 {
+			break
+		}
+
+		// loudest bin of the band, at least the one nearest to its start
+		low := spectrumLowHz * math.Exp2(octaves*float64(band)/spectrumBands)
+		high := spectrumLowHz * math.Exp2(octaves*float64(band+1)/spectrumBands)
+		first := min(int(math.Round(low/binHz)), len(v.spectrum)-1)
+		last := min(max(int(high/binHz), first), len(v.spectrum)-1)
+
+		amplitude := float32(0)
+		for _, a := range v.spectrum[first : last+1] {
+			amplitude = max(amplitude, a)
+		}
+
+		h := levelHeight(amplitude)
+		v.rect(x+float64(band)*width, bottom-h*visualizerHeight, width-1, h*visualizerHeight, levelColor(h))
+	}
+
+	v.layer.Render(v.sprites...)
+}
+
+func (v *visualizer) rect(x, y, w, h float64, color [4]float32) {
+	s := v.bar
+	s.Pos = gmath.Rectf64{X: x, Y: y, W: w, H: h}
+	s.Color = color
+	v.sprites = append(v.sprites, s)
+}
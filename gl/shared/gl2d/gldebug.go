@@ -0,0 +1,37 @@
+//go:build !goarrg_build_debug && !goarrg_disable_gl
+// +build !goarrg_build_debug,!goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gl2d
+
+/*
+	#include <GL/gl.h>
+*/
+import "C"
+
+import (
+	"goarrg.com"
+)
+
+// GL error reporting is only done in debug builds, see gldebug_debug.go
+
+func glDebugInit(goarrg.GLInstance) {}
+
+func glCheck(string) bool { return true }
+
+func glLabelTexture(C.GLuint, string) {}
@@ -0,0 +1,67 @@
+//go:build goarrg_build_debug && !goarrg_disable_gl
+// +build goarrg_build_debug,!goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+#include <stddef.h>
+#include <stdint.h>
+
+#include <GL/gl.h>
+#include <GL/glext.h>
+
+#include "_cgo_export.h"
+
+typedef void* (*gl2dGetProcAddrFn)(const char*);
+
+static PFNGLDEBUGMESSAGECALLBACKPROC gl2dDebugMessageCallback = NULL;
+static PFNGLOBJECTLABELPROC gl2dObjectLabel = NULL;
+
+static void APIENTRY gl2dDebugCallback(GLenum source,
+									   GLenum type,
+									   GLuint id,
+									   GLenum severity,
+									   GLsizei length,
+									   const GLchar* message,
+									   const void* userParam) {
+	(void)userParam;
+	gl2dDebugMessage(source, type, id, severity, length, (GLchar*)message);
+}
+
+int gl2dDebugInit(uintptr_t getProcAddr) {
+	gl2dGetProcAddrFn fn = (gl2dGetProcAddrFn)getProcAddr;
+
+	gl2dDebugMessageCallback =
+		(PFNGLDEBUGMESSAGECALLBACKPROC)fn("glDebugMessageCallback");
+	gl2dObjectLabel = (PFNGLOBJECTLABELPROC)fn("glObjectLabel");
+
+	if (!gl2dDebugMessageCallback || !gl2dObjectLabel) {
+		gl2dDebugMessageCallback = NULL;
+		gl2dObjectLabel = NULL;
+		return 0;
+	}
+
+	glEnable(GL_DEBUG_OUTPUT);
+	glEnable(GL_DEBUG_OUTPUT_SYNCHRONOUS);
+	gl2dDebugMessageCallback(gl2dDebugCallback, NULL);
+	return 1;
+}
+
+void gl2dObjectLabelTexture(GLuint id, const char* label) {
+	if (gl2dObjectLabel) {
+		gl2dObjectLabel(GL_TEXTURE, id, -1, label);
+	}
+}
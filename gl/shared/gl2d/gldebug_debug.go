@@ -0,0 +1,196 @@
+//go:build goarrg_build_debug && !goarrg_disable_gl
+// +build goarrg_build_debug,!goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gl2d
+
+/*
+	#include <stdint.h>
+	#include <stdlib.h>
+	#include <GL/gl.h>
+	#include <GL/glext.h>
+
+	extern int gl2dDebugInit(uintptr_t getProcAddr);
+	extern void gl2dObjectLabelTexture(GLuint id, const char* label);
+*/
+import "C"
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	"goarrg.com"
+	"goarrg.com/debug"
+)
+
+// set when KHR_debug reports errors for us, otherwise glCheck polls glGetError
+var glDebugOutput bool
+
+/*
+installs a KHR_debug message callback when the context supports it, otherwise
+every glCheck call falls back to draining glGetError
+*/
+func glDebugInit(glInstance goarrg.GLInstance) {
+	glDebugOutput = false
+
+	if !glHasKHRDebug() {
+		debug.WPrintf("GL_KHR_debug not supported, falling back to glGetError")
+		return
+	}
+
+	glDebugOutput = C.gl2dDebugInit(C.uintptr_t(glInstance.ProcAddr())) != 0
+	if !glDebugOutput {
+		debug.WPrintf("Failed to load GL_KHR_debug, falling back to glGetError")
+	}
+}
+
+func glHasKHRDebug() bool {
+	// KHR_debug is core since 4.3
+	major, minor := 0, 0
+	version := C.GoString((*C.char)(unsafe.Pointer(C.glGetString(C.GL_VERSION))))
+	if _, err := fmt.Sscanf(version, "%d.%d", &major, &minor); err == nil {
+		if major > 4 || (major == 4 && minor >= 3) {
+			return true
+		}
+	}
+
+	extensions := C.GoString((*C.char)(unsafe.Pointer(C.glGetString(C.GL_EXTENSIONS))))
+	for _, e := range strings.Fields(extensions) {
+		if e == "GL_KHR_debug" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// reports every pending GL error, op names the call that is being checked.
+// Returns false if an error was reported, errors reported through KHR_debug
+// arrive in the callback instead.
+func glCheck(op string) bool {
+	if glDebugOutput {
+		return true
+	}
+
+	ok := true
+	for glErr := C.glGetError(); glErr != C.GL_NO_ERROR; glErr = C.glGetError() {
+		debug.EPrintf("%s: %s", op, glErrorString(glErr))
+		ok = false
+	}
+	return ok
+}
+
+// names the texture after its file in GL debug messages and tools
+func glLabelTexture(id C.GLuint, filename string) {
+	label := C.CString(filename)
+	C.gl2dObjectLabelTexture(id, label)
+	C.free(unsafe.Pointer(label))
+}
+
+//export gl2dDebugMessage
+func gl2dDebugMessage(source, typ C.GLenum, id C.GLuint, severity C.GLenum, length C.GLsizei, message *C.GLchar) {
+	// a negative length means the message is null terminated
+	var msg string
+	if length < 0 {
+		msg = C.GoString((*C.char)(unsafe.Pointer(message)))
+	} else {
+		msg = C.GoStringN((*C.char)(unsafe.Pointer(message)), C.int(length))
+	}
+
+	format := "GL [%s] [%s] [%s] %d: %s"
+	args := []any{glDebugSourceString(source), glDebugTypeString(typ), glDebugSeverityString(severity), uint32(id), msg}
+
+	switch {
+	case typ == C.GL_DEBUG_TYPE_ERROR || severity == C.GL_DEBUG_SEVERITY_HIGH:
+		debug.EPrintf(format, args...)
+	case severity == C.GL_DEBUG_SEVERITY_NOTIFICATION:
+		debug.VPrintf(format, args...)
+	default:
+		debug.WPrintf(format, args...)
+	}
+}
+
+func glErrorString(glErr C.GLenum) string {
+	switch glErr {
+	case C.GL_INVALID_ENUM:
+		return "GL_INVALID_ENUM"
+	case C.GL_INVALID_VALUE:
+		return "GL_INVALID_VALUE"
+	case C.GL_INVALID_OPERATION:
+		return "GL_INVALID_OPERATION"
+	case C.GL_STACK_OVERFLOW:
+		return "GL_STACK_OVERFLOW"
+	case C.GL_STACK_UNDERFLOW:
+		return "GL_STACK_UNDERFLOW"
+	case C.GL_OUT_OF_MEMORY:
+		return "GL_OUT_OF_MEMORY"
+	default:
+		return "0x" + strconv.FormatUint(uint64(glErr), 16)
+	}
+}
+
+func glDebugSourceString(source C.GLenum) string {
+	switch source {
+	case C.GL_DEBUG_SOURCE_API:
+		return "API"
+	case C.GL_DEBUG_SOURCE_WINDOW_SYSTEM:
+		return "Window System"
+	case C.GL_DEBUG_SOURCE_SHADER_COMPILER:
+		return "Shader Compiler"
+	case C.GL_DEBUG_SOURCE_THIRD_PARTY:
+		return "Third Party"
+	case C.GL_DEBUG_SOURCE_APPLICATION:
+		return "Application"
+	default:
+		return "Other"
+	}
+}
+
+func glDebugTypeString(typ C.GLenum) string {
+	switch typ {
+	case C.GL_DEBUG_TYPE_ERROR:
+		return "Error"
+	case C.GL_DEBUG_TYPE_DEPRECATED_BEHAVIOR:
+		return "Deprecated"
+	case C.GL_DEBUG_TYPE_UNDEFINED_BEHAVIOR:
+		return "Undefined"
+	case C.GL_DEBUG_TYPE_PORTABILITY:
+		return "Portability"
+	case C.GL_DEBUG_TYPE_PERFORMANCE:
+		return "Performance"
+	case C.GL_DEBUG_TYPE_MARKER:
+		return "Marker"
+	default:
+		return "Other"
+	}
+}
+
+func glDebugSeverityString(severity C.GLenum) string {
+	switch severity {
+	case C.GL_DEBUG_SEVERITY_HIGH:
+		return "High"
+	case C.GL_DEBUG_SEVERITY_MEDIUM:
+		return "Medium"
+	case C.GL_DEBUG_SEVERITY_LOW:
+		return "Low"
+	default:
+		return "Notification"
+	}
+}
@@ -0,0 +1,109 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gl2d
+
+/*
+	#include <stdint.h>
+	#include <stdlib.h>
+	#include <GL/gl.h>
+
+	typedef GLenum (APIENTRY *gl2dGetGraphicsResetStatusFn)(void);
+
+	static gl2dGetGraphicsResetStatusFn gl2dGetGraphicsResetStatus = NULL;
+
+	static void gl2dResetInit(uintptr_t getProcAddr, const char* name) {
+		void* (*fn)(const char*) = (void* (*)(const char*))getProcAddr;
+		gl2dGetGraphicsResetStatus = NULL;
+		if (name) {
+			gl2dGetGraphicsResetStatus = (gl2dGetGraphicsResetStatusFn)fn(name);
+		}
+	}
+
+	static GLenum gl2dResetStatus(void) {
+		if (!gl2dGetGraphicsResetStatus) {
+			return GL_NO_ERROR;
+		}
+		return gl2dGetGraphicsResetStatus();
+	}
+*/
+import "C"
+
+import (
+	"fmt"
+	"strings"
+	"unsafe"
+
+	"goarrg.com"
+	"goarrg.com/debug"
+)
+
+/*
+loads glGetGraphicsResetStatus when the context supports it, otherwise resets
+are never reported. Drivers only report resets on contexts created with a
+reset notification strategy, which is up to the platform.
+*/
+func glResetInit(glInstance goarrg.GLInstance) {
+	name := glResetStatusName()
+	if name == "" {
+		debug.WPrintf("GL robustness not supported, context resets will not be detected")
+		C.gl2dResetInit(C.uintptr_t(glInstance.ProcAddr()), nil)
+		return
+	}
+
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	C.gl2dResetInit(C.uintptr_t(glInstance.ProcAddr()), cName)
+}
+
+// the entry point to load, the proc address may be non nil even for
+// functions the context does not support so it cannot be probed
+func glResetStatusName() string {
+	// robustness is core since 4.5
+	major, minor := 0, 0
+	version := C.GoString((*C.char)(unsafe.Pointer(C.glGetString(C.GL_VERSION))))
+	if _, err := fmt.Sscanf(version, "%d.%d", &major, &minor); err == nil {
+		if major > 4 || (major == 4 && minor >= 5) {
+			return "glGetGraphicsResetStatus"
+		}
+	}
+
+	extensions := C.GoString((*C.char)(unsafe.Pointer(C.glGetString(C.GL_EXTENSIONS))))
+	for _, e := range strings.Fields(extensions) {
+		switch e {
+		case "GL_KHR_robustness":
+			return "glGetGraphicsResetStatus"
+		case "GL_ARB_robustness":
+			return "glGetGraphicsResetStatusARB"
+		}
+	}
+
+	return ""
+}
+
+// reports whether the context was reset since the last call
+func glContextReset() bool {
+	status := C.gl2dResetStatus()
+	if status == C.GL_NO_ERROR {
+		return false
+	}
+
+	debug.EPrintf("GL context was reset: 0x%X", uint32(status))
+	return true
+}
@@ -0,0 +1,193 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gl2d
+
+import (
+	"math"
+	"slices"
+
+	"goarrg.com/debug"
+	"goarrg.com/gmath"
+)
+
+// name of the layer targeted by Render and ScreenPosToWorld
+const DefaultLayerName = "default"
+
+/*
+Camera maps world space into the renderer's resolution space, the zero value
+is the identity so a layer that never sets a camera is fixed to the screen.
+*/
+type Camera struct {
+	// world position of the top left corner of the view when not zoomed or rotated
+	Pos gmath.Point3f64
+	// zoom around the center of the view, 0 is treated as 1
+	Zoom float64
+	// rotation in radians around the center of the view
+	Rotation float64
+}
+
+type LayerConfig struct {
+	// layers are drawn in ascending order, layers with the same order are
+	// drawn in creation order, the default layer has order 0
+	Order int
+	// clears the screen with ClearColor before drawing the layer
+	Clear      bool
+	ClearColor [4]float32
+	// when > 0, Pick ignores sprite texels whose alpha multiplied by the
+	// sprite's Color[3] is below the threshold
+	PickAlphaThreshold float32
+}
+
+type Layer struct {
+	name string
+	cfg  LayerConfig
+
+	// everything below is guarded by Renderer.submitLock
+	camera      Camera
+	back        []Sprite
+	front       []Sprite
+	frontCamera Camera
+
+	// guarded by Renderer.pickLock, rebuilt lazily for the frame in front
+	pick pickIndex
+}
+
+func newLayer(name string, cfg LayerConfig) *Layer {
+	return &Layer{name: name, cfg: cfg}
+}
+
+// creates a new layer, names must be unique
+func LayerCreate(name string, cfg LayerConfig) (*Layer, error) {
+	Renderer.submitLock.Lock()
+	defer Renderer.submitLock.Unlock()
+
+	if slices.ContainsFunc(Renderer.layers, func(l *Layer) bool { return l.name == name }) {
+		return nil, debug.Errorf("Layer %q already exists", name)
+	}
+
+	l := newLayer(name, cfg)
+	Renderer.layers = append(Renderer.layers, l)
+	slices.SortStableFunc(Renderer.layers, func(a, b *Layer) int {
+		return a.cfg.Order - b.cfg.Order
+	})
+
+	return l, nil
+}
+
+// returns the layer with the given name or nil if it does not exist
+func LayerGet(name string) *Layer {
+	Renderer.submitLock.Lock()
+	defer Renderer.submitLock.Unlock()
+
+	for _, l := range Renderer.layers {
+		if l.name == name {
+			return l
+		}
+	}
+
+	return nil
+}
+
+func DefaultLayer() *Layer {
+	return Renderer.defaultLayer
+}
+
+func (l *Layer) Name() string {
+	return l.name
+}
+
+func (l *Layer) Camera() Camera {
+	Renderer.submitLock.Lock()
+	defer Renderer.submitLock.Unlock()
+
+	return l.camera
+}
+
+// the camera is latched together with the sprites at the start of every
+// frame so sprites are always drawn with the camera they were submitted under
+func (l *Layer) SetCamera(c Camera) {
+	Renderer.submitLock.Lock()
+	l.camera = c
+	Renderer.submitLock.Unlock()
+}
+
+// same as the package level Render but targets this layer
+func (l *Layer) Render(sprites ...Sprite) {
+	Renderer.submitLock.Lock()
+	l.back = appendVisible(l.back, sprites)
+	Renderer.submitLock.Unlock()
+}
+
+// converts a position in window coordinates into the layer's world space
+func (l *Layer) ScreenPosToWorld(pos gmath.Point3f64) gmath.Point3f64 {
+	return l.Camera().resToWorld(screenPosToRes(pos))
+}
+
+// converts a position in the layer's world space into window coordinates
+func (l *Layer) WorldPosToScreen(pos gmath.Point3f64) gmath.Point3f64 {
+	return resPosToScreen(l.Camera().worldToRes(pos))
+}
+
+// returns the world position at the center of the view, zoom and rotation
+// keep it in place so it is where a listener for positional audio belongs
+func (l *Layer) ViewCenter() gmath.Point3f64 {
+	c := l.Camera()
+	return c.resToWorld(gmath.Point3f64(c.center()))
+}
+
+func (c Camera) zoom() float64 {
+	if c.Zoom == 0 {
+		return 1
+	}
+	return c.Zoom
+}
+
+func (c Camera) center() gmath.Vector3f64 {
+	return gmath.Vector3f64{X: float64(Renderer.resW) / 2, Y: float64(Renderer.resH) / 2}
+}
+
+func (c Camera) worldToRes(p gmath.Point3f64) gmath.Point3f64 {
+	center := c.center()
+	v := gmath.Vector3f64(p).Subtract(gmath.Vector3f64(c.Pos)).Subtract(center)
+	v = rotate(v, -c.Rotation).ScaleUniform(c.zoom())
+	return gmath.Point3f64(v.Add(center))
+}
+
+func (c Camera) resToWorld(p gmath.Point3f64) gmath.Point3f64 {
+	center := c.center()
+	v := gmath.Vector3f64(p).Subtract(center).ScaleInverseUniform(c.zoom())
+	v = rotate(v, c.Rotation)
+	return gmath.Point3f64(v.Add(center).Add(gmath.Vector3f64(c.Pos)))
+}
+
+func rotate(v gmath.Vector3f64, radians float64) gmath.Vector3f64 {
+	sin, cos := math.Sincos(radians)
+	return gmath.Vector3f64{X: v.X*cos - v.Y*sin, Y: v.X*sin + v.Y*cos, Z: v.Z}
+}
+
+func screenPosToRes(pos gmath.Point3f64) gmath.Point3f64 {
+	ndc := gmath.Vector3f64(pos).ScaleInverse(gmath.Vector3f64{X: float64(Renderer.screenW), Y: float64(Renderer.screenH), Z: 1})
+	return gmath.Point3f64(ndc.Scale(gmath.Vector3f64{X: float64(Renderer.resW), Y: float64(Renderer.resH), Z: 1}))
+}
+
+func resPosToScreen(pos gmath.Point3f64) gmath.Point3f64 {
+	ndc := gmath.Vector3f64(pos).ScaleInverse(gmath.Vector3f64{X: float64(Renderer.resW), Y: float64(Renderer.resH), Z: 1})
+	return gmath.Point3f64(ndc.Scale(gmath.Vector3f64{X: float64(Renderer.screenW), Y: float64(Renderer.screenH), Z: 1}))
+}
@@ -0,0 +1,86 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gl2d
+
+import (
+	"math"
+	"testing"
+
+	"goarrg.com/gmath"
+)
+
+func TestLayerOrder(t *testing.T) {
+	hud, err := LayerCreate("test_hud", LayerConfig{Order: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	world, err := LayerCreate("test_world", LayerConfig{Order: -10})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LayerCreate("test_world", LayerConfig{}); err == nil {
+		t.Fatal("Created duplicate layer")
+	}
+	if LayerGet("test_hud") != hud {
+		t.Fatal("LayerGet returned the wrong layer")
+	}
+
+	want := []*Layer{world, Renderer.defaultLayer, hud}
+	for i, l := range want {
+		if Renderer.layers[i] != l {
+			t.Fatalf("Layer %d is %q, want %q", i, Renderer.layers[i].name, l.name)
+		}
+	}
+}
+
+func TestCameraScreenPosToWorld(t *testing.T) {
+	Renderer.resW, Renderer.resH = 800, 600
+	Renderer.screenW, Renderer.screenH = 1600, 1200
+
+	l := newLayer("test_camera", LayerConfig{})
+	screen := gmath.Point3f64{X: 400, Y: 300}
+
+	if p := l.ScreenPosToWorld(screen); p != (gmath.Point3f64{X: 200, Y: 150}) {
+		t.Fatalf("Identity camera mapped %v to %v", screen, p)
+	}
+
+	l.SetCamera(Camera{Pos: gmath.Point3f64{X: 100, Y: -50}, Zoom: 2})
+	if p := l.ScreenPosToWorld(screen); p != (gmath.Point3f64{X: 400, Y: 175}) {
+		t.Fatalf("Zoomed camera mapped %v to %v", screen, p)
+	}
+
+	l.SetCamera(Camera{Pos: gmath.Point3f64{X: 10, Y: 20}, Zoom: 0.5, Rotation: math.Pi / 3})
+	p := l.ScreenPosToWorld(screen)
+	if back := l.WorldPosToScreen(p); math.Abs(back.X-screen.X) > 1e-9 || math.Abs(back.Y-screen.Y) > 1e-9 {
+		t.Fatalf("Round trip of %v returned %v", screen, back)
+	}
+}
+
+func TestCameraViewCenter(t *testing.T) {
+	Renderer.resW, Renderer.resH = 800, 600
+
+	l := newLayer("test_view_center", LayerConfig{})
+	l.SetCamera(Camera{Pos: gmath.Point3f64{X: 100, Y: -50}, Zoom: 3, Rotation: 1})
+
+	if p := l.ViewCenter(); math.Abs(p.X-500) > 1e-9 || math.Abs(p.Y-250) > 1e-9 {
+		t.Fatalf("View center is %v, want (500, 250)", p)
+	}
+}
@@ -0,0 +1,171 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gl2d
+
+import (
+	"math"
+
+	"goarrg.com/gmath"
+)
+
+const (
+	pickMinCellSize = 16
+	// sprites covering more cells than this are tested on every query instead
+	pickMaxCells = 64
+)
+
+type pickCell struct {
+	x, y int
+}
+
+/*
+pickIndex is a sparse uniform grid over a layer's front buffer, cells hold
+sprite indices in submission order so the last hit in a cell is the top-most.
+*/
+type pickIndex struct {
+	built    bool
+	cellSize float64
+	cells    map[pickCell][]int
+	overflow []int
+}
+
+// returns the top-most sprite drawn last frame under pos which is in window
+// coordinates, layers drawn later are tested first
+func Pick(pos gmath.Point3f64) (Sprite, *Layer, bool) {
+	Renderer.pickLock.Lock()
+	defer Renderer.pickLock.Unlock()
+
+	res := screenPosToRes(pos)
+	for i := len(Renderer.drawLayers) - 1; i >= 0; i-- {
+		l := Renderer.drawLayers[i]
+		if s, ok := l.pickLocked(res); ok {
+			return s, l, true
+		}
+	}
+
+	return Sprite{}, nil, false
+}
+
+// same as the package level Pick but only tests this layer
+func (l *Layer) Pick(pos gmath.Point3f64) (Sprite, bool) {
+	Renderer.pickLock.Lock()
+	defer Renderer.pickLock.Unlock()
+
+	return l.pickLocked(screenPosToRes(pos))
+}
+
+func (l *Layer) pickLocked(res gmath.Point3f64) (Sprite, bool) {
+	if !l.pick.built {
+		l.pick.build(l.front)
+	}
+
+	p := l.frontCamera.resToWorld(res)
+	hit := func(i int) bool {
+		return pickTest(l.front[i], p, l.cfg.PickAlphaThreshold)
+	}
+
+	best := -1
+	cell := l.pick.cellOf(p.X, p.Y)
+	indices := l.pick.cells[cell]
+
+	for i := len(indices) - 1; i >= 0; i-- {
+		if hit(indices[i]) {
+			best = indices[i]
+			break
+		}
+	}
+	for i := len(l.pick.overflow) - 1; i >= 0 && l.pick.overflow[i] > best; i-- {
+		if hit(l.pick.overflow[i]) {
+			best = l.pick.overflow[i]
+			break
+		}
+	}
+
+	if best < 0 {
+		return Sprite{}, false
+	}
+	return l.front[best], true
+}
+
+func (idx *pickIndex) build(sprites []Sprite) {
+	if idx.cells == nil {
+		idx.cells = make(map[pickCell][]int)
+	}
+	clear(idx.cells)
+	idx.overflow = idx.overflow[:0]
+	idx.built = true
+
+	if len(sprites) == 0 {
+		return
+	}
+
+	// size cells after the average sprite so most sprites touch few cells
+	size := 0.0
+	for _, s := range sprites {
+		size += max(math.Abs(s.Pos.W), math.Abs(s.Pos.H))
+	}
+	idx.cellSize = max(size/float64(len(sprites)), pickMinCellSize)
+
+	for i, s := range sprites {
+		x0, y0, x1, y1 := spriteBounds(s)
+		c0 := idx.cellOf(x0, y0)
+		c1 := idx.cellOf(x1, y1)
+
+		if (c1.x-c0.x+1)*(c1.y-c0.y+1) > pickMaxCells {
+			idx.overflow = append(idx.overflow, i)
+			continue
+		}
+
+		for y := c0.y; y <= c1.y; y++ {
+			for x := c0.x; x <= c1.x; x++ {
+				c := pickCell{x, y}
+				idx.cells[c] = append(idx.cells[c], i)
+			}
+		}
+	}
+}
+
+func (idx *pickIndex) cellOf(x, y float64) pickCell {
+	return pickCell{int(math.Floor(x / idx.cellSize)), int(math.Floor(y / idx.cellSize))}
+}
+
+func spriteBounds(s Sprite) (float64, float64, float64, float64) {
+	return min(s.Pos.X, s.Pos.X+s.Pos.W), min(s.Pos.Y, s.Pos.Y+s.Pos.H),
+		max(s.Pos.X, s.Pos.X+s.Pos.W), max(s.Pos.Y, s.Pos.Y+s.Pos.H)
+}
+
+func pickTest(s Sprite, p gmath.Point3f64, alphaThreshold float32) bool {
+	x0, y0, x1, y1 := spriteBounds(s)
+	if p.X < x0 || p.X >= x1 || p.Y < y0 || p.Y >= y1 {
+		return false
+	}
+
+	if alphaThreshold <= 0 {
+		return true
+	}
+
+	// map into the clip rect, flipped sprites have a negative size
+	u := (p.X - s.Pos.X) / s.Pos.W
+	v := (p.Y - s.Pos.Y) / s.Pos.H
+	tx := s.Clip.X + int(u*float64(s.Clip.W))
+	ty := s.Clip.Y + int(v*float64(s.Clip.H))
+
+	return s.texture.alphaAt(tx, ty)*s.Color[3] >= alphaThreshold
+}
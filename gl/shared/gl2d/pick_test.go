@@ -0,0 +1,118 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gl2d
+
+import (
+	"math/rand"
+	"testing"
+
+	"goarrg.com/gmath"
+)
+
+func pickTestSprite(name string, x, y, w, h float64) Sprite {
+	return Sprite{
+		texture: &texture{filename: name, resolution: gmath.Vector3int{X: 2, Y: 2}},
+		Pos:     gmath.Rectf64{X: x, Y: y, W: w, H: h},
+		Clip:    gmath.Rectint{W: 2, H: 2},
+		Color:   [4]float32{1, 1, 1, 1},
+	}
+}
+
+func TestPick(t *testing.T) {
+	Renderer.resW, Renderer.resH = 800, 600
+	Renderer.screenW, Renderer.screenH = 800, 600
+
+	world, err := LayerCreate("test_pick_world", LayerConfig{Order: -100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	world.SetCamera(Camera{Pos: gmath.Point3f64{X: 1000}})
+
+	hud, err := LayerCreate("test_pick_hud", LayerConfig{Order: 100, PickAlphaThreshold: 0.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// left half transparent
+	button := pickTestSprite("button", 0, 0, 100, 100)
+	button.texture.alpha = []uint8{0, 255, 0, 255}
+
+	world.Render(pickTestSprite("a", 1000, 0, 100, 100), pickTestSprite("b", 1050, 0, 100, 100))
+	hud.Render(button)
+	Renderer.swapFrame()
+	defer Renderer.swapFrame()
+
+	tests := []struct {
+		pos   gmath.Point3f64
+		want  string
+		layer *Layer
+	}{
+		{gmath.Point3f64{X: 10, Y: 10}, "a", world},
+		{gmath.Point3f64{X: 75, Y: 10}, "button", hud},
+		{gmath.Point3f64{X: 125, Y: 10}, "b", world},
+		{gmath.Point3f64{X: 300, Y: 10}, "", nil},
+	}
+
+	for _, test := range tests {
+		s, l, ok := Pick(test.pos)
+		if test.layer == nil {
+			if ok {
+				t.Fatalf("Picked %q at %v, want nothing", s.texture.filename, test.pos)
+			}
+			continue
+		}
+		if !ok || s.texture.filename != test.want || l != test.layer {
+			t.Fatalf("Pick at %v returned %v %v, want %q on %q", test.pos, s.texture, ok, test.want, test.layer.name)
+		}
+	}
+}
+
+func TestPickIndex(t *testing.T) {
+	Renderer.resW, Renderer.resH = 800, 600
+	Renderer.screenW, Renderer.screenH = 800, 600
+
+	l := newLayer("test_pick_index", LayerConfig{})
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 5000; i++ {
+		w, h := r.Float64()*64, r.Float64()*64
+		if i%100 == 0 {
+			w, h = r.Float64()*2000, -r.Float64()*2000
+		}
+		l.front = append(l.front, pickTestSprite("", r.Float64()*2000-1000, r.Float64()*2000-1000, w, h))
+	}
+
+	for i := 0; i < 1000; i++ {
+		p := gmath.Point3f64{X: r.Float64()*2000 - 1000, Y: r.Float64()*2000 - 1000}
+
+		want := -1
+		for j := len(l.front) - 1; j >= 0; j-- {
+			if pickTest(l.front[j], p, 0) {
+				want = j
+				break
+			}
+		}
+
+		s, ok := l.pickLocked(p)
+		if ok != (want >= 0) || (ok && s != l.front[want]) {
+			t.Fatalf("Pick at %v disagrees with a linear search", p)
+		}
+	}
+}
@@ -27,6 +27,7 @@ package gl2d
 import "C"
 
 import (
+	"math"
 	"sync"
 	"time"
 
@@ -40,10 +41,21 @@ type gl2d struct {
 	jobs chan func()
 
 	glInstance goarrg.GLInstance
+	// incremented every time GLInit is called with a new context
+	glGeneration uint64
 
 	textureLock sync.RWMutex
 	textures    map[string]texture
-	sprites     []Sprite
+
+	// Render appends to each layer's back buffer while Draw consumes the
+	// front buffers, they are swapped under submitLock at the start of every Draw
+	submitLock   sync.Mutex
+	layers       []*Layer
+	defaultLayer *Layer
+	drawLayers   []*Layer
+
+	// held while the front buffers change so Pick can read the last frame
+	pickLock sync.Mutex
 
 	screenW int
 	screenH int
@@ -54,10 +66,15 @@ type gl2d struct {
 	lastTime time.Time
 }
 
-var Renderer = &gl2d{
-	jobs:     make(chan func(), 8),
-	textures: make(map[string]texture),
-}
+var Renderer = func() *gl2d {
+	l := newLayer(DefaultLayerName, LayerConfig{})
+	return &gl2d{
+		jobs:         make(chan func(), 8),
+		textures:     make(map[string]texture),
+		layers:       []*Layer{l},
+		defaultLayer: l,
+	}
+}()
 
 type Config struct {
 	ResW int
@@ -81,13 +98,28 @@ func (r *gl2d) GLConfig() goarrg.GLConfig {
 	return goarrg.GLConfig{}
 }
 
-// window and gl instance was created so now time to init the renderer
+/*
+GLInit is called once the window and gl instance were created to init the
+renderer. When the context supports robustness Draw polls for a reset once per
+frame and calls GLInit again itself, otherwise the host must call GLInit again
+with the new context after recreating it. Either way every cached texture is
+decoded from disk and re-uploaded on the render thread before the next frame is
+drawn, which stalls that frame for as long as the decoding takes.
+*/
 func (r *gl2d) GLInit(_ goarrg.PlatformInterface, glInstance goarrg.GLInstance) error {
+	glDebugInit(glInstance)
+	glResetInit(glInstance)
+
 	C.glClearColor(0, 0, 0, 1)
+	glCheck("glClearColor")
 	C.glEnable(C.GL_BLEND)
+	glCheck("glEnable(GL_BLEND)")
 	C.glBlendFunc(C.GL_SRC_ALPHA, C.GL_ONE_MINUS_SRC_ALPHA)
+	glCheck("glBlendFunc")
 	C.glDisable(C.GL_DEPTH_TEST)
+	glCheck("glDisable(GL_DEPTH_TEST)")
 	C.glEnable(C.GL_CULL_FACE)
+	glCheck("glEnable(GL_CULL_FACE)")
 	// C.glEnable(C.GL_MULTISAMPLE_ARB)
 
 	if r.resW <= 0 || r.resH <= 0 {
@@ -95,13 +127,35 @@ func (r *gl2d) GLInit(_ goarrg.PlatformInterface, glInstance goarrg.GLInstance)
 		Renderer.resH = 600
 	}
 
+	r.contextInit(glInstance)
+	return nil
+}
+
+// starts a new context generation, reloading the textures of the previous one
+func (r *gl2d) contextInit(glInstance goarrg.GLInstance) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
 	r.glInstance = glInstance
+	r.glGeneration++
 	r.lastTime = time.Now()
-	return nil
+
+	// every texture id belonged to the old context
+	if r.glGeneration > 1 {
+		debug.IPrintf("GL context recreated, reloading textures")
+		r.textureReloadAll()
+	}
 }
 
 // time to draw, returns deltatime
 func (r *gl2d) Draw() float64 {
+	// a reset loses every object of the context, rebuild them before drawing
+	if glContextReset() {
+		if err := r.GLInit(nil, r.glInstance); err != nil {
+			debug.EPrintf("Failed to rebuild the GL context: %v", err)
+		}
+	}
+
 	r.lock.Lock()
 	defer r.lock.Unlock()
 
@@ -119,25 +173,57 @@ lJobs:
 		}
 	}
 
+	r.swapFrame()
+
 	C.glClear(C.GL_COLOR_BUFFER_BIT)
+	glCheck("glClear")
 
 	C.glViewport(0, 0, C.int(r.screenW), C.int(r.screenH))
+	glCheck("glViewport")
 
 	C.glMatrixMode(C.GL_PROJECTION)
+	glCheck("glMatrixMode(GL_PROJECTION)")
 	C.glLoadIdentity()
+	glCheck("glLoadIdentity")
 	C.glOrtho(0, C.double(r.resW), C.double(r.resH), 0, 0, 1)
-
-	C.glMatrixMode(C.GL_MODELVIEW)
-	C.glLoadIdentity()
-	// C.glTranslatef(x, y, 0)
-	// glScalef(zoom, zoom, 1)
-	// glRotatef(rotation, 0, 0, 1)
+	glCheck("glOrtho")
 
 	C.glEnable(C.GL_TEXTURE_2D)
+	glCheck("glEnable(GL_TEXTURE_2D)")
+
+	for _, l := range r.drawLayers {
+		if l.cfg.Clear {
+			C.glClearColor(C.float(l.cfg.ClearColor[0]), C.float(l.cfg.ClearColor[1]), C.float(l.cfg.ClearColor[2]), C.float(l.cfg.ClearColor[3]))
+			C.glClear(C.GL_COLOR_BUFFER_BIT)
+			C.glClearColor(0, 0, 0, 1)
+			glCheck("layer clear")
+		}
+
+		C.glMatrixMode(C.GL_MODELVIEW)
+		glCheck("glMatrixMode(GL_MODELVIEW)")
+		C.glLoadIdentity()
+		glCheck("glLoadIdentity")
 
-	for _, s := range r.sprites {
+		c := l.frontCamera
+		center := c.center()
+		C.glTranslatef(C.float(center.X), C.float(center.Y), 0)
+		C.glScalef(C.float(c.zoom()), C.float(c.zoom()), 1)
+		C.glRotatef(C.float(-c.Rotation*180/math.Pi), 0, 0, 1)
+		C.glTranslatef(C.float(-c.Pos.X-center.X), C.float(-c.Pos.Y-center.Y), 0)
+		glCheck("camera transform")
+
+		r.drawSprites(l.front)
+	}
+
+	r.glInstance.SwapBuffers()
+
+	return deltaTime
+}
+
+func (r *gl2d) drawSprites(sprites []Sprite) {
+	for _, s := range sprites {
 		C.glPushMatrix()
-		C.glBindTexture(C.GL_TEXTURE_2D, s.texture.id)
+		C.glBindTexture(C.GL_TEXTURE_2D, s.texture.gl.id)
 
 		// glTexParameterf(GL_TEXTURE_2D, GL_TEXTURE_WRAP_S, GL_REPEAT)
 		// glTexParameterf(GL_TEXTURE_2D, GL_TEXTURE_WRAP_T, GL_REPEAT)
@@ -146,6 +232,8 @@ lJobs:
 		C.glTexParameterf(C.GL_TEXTURE_2D, C.GL_TEXTURE_WRAP_T, C.GL_CLAMP)
 
 		C.glTranslatef(C.float(s.Pos.X), C.float(s.Pos.Y), 0)
+		// glGetError is not allowed between glBegin and glEnd
+		glCheck("sprite setup")
 
 		C.glBegin(C.GL_TRIANGLE_STRIP)
 		{
@@ -179,12 +267,8 @@ lJobs:
 
 		C.glBindTexture(C.GL_TEXTURE_2D, 0)
 		C.glPopMatrix()
+		glCheck("drawSprites")
 	}
-
-	r.sprites = r.sprites[:0]
-	r.glInstance.SwapBuffers()
-
-	return deltaTime
 }
 
 // window was resized, w and h are the drawable surface size
@@ -200,22 +284,95 @@ func (r *gl2d) Resize(w, h int) {
 func (r *gl2d) Destroy() {
 }
 
+// swaps the back buffers in as the frame to draw, and recycles the previous
+// frame as the new back buffers
+func (r *gl2d) swapFrame() {
+	r.pickLock.Lock()
+	defer r.pickLock.Unlock()
+
+	// drop the texture references so they can be collected
+	for _, l := range r.drawLayers {
+		clear(l.front)
+	}
+
+	r.submitLock.Lock()
+	r.drawLayers = append(r.drawLayers[:0], r.layers...)
+	for _, l := range r.drawLayers {
+		l.front, l.back = l.back, l.front[:0]
+		l.frontCamera = l.camera
+		l.pick.built = false
+	}
+	r.submitLock.Unlock()
+}
+
 func (r *gl2d) runAsync(f func()) {
 	go func() {
 		r.jobs <- f
 	}()
 }
 
+// converts a position in window coordinates into world space of the default layer
 func ScreenPosToWorld(pos gmath.Point3f64) gmath.Point3f64 {
-	ndc := gmath.Vector3f64(pos).ScaleInverse(gmath.Vector3f64{X: float64(Renderer.screenW), Y: float64(Renderer.screenH)})
-	return gmath.Point3f64(ndc.Scale(gmath.Vector3f64{X: float64(Renderer.resW), Y: float64(Renderer.resH)}))
+	return Renderer.defaultLayer.ScreenPosToWorld(pos)
 }
 
-// call this function to draw stuff, renderer does not draw anything you don't tell it to
+// call this function to draw stuff, renderer does not draw anything you don't tell it to.
+// Sprites are drawn on the default layer, use Layer.Render to target other layers.
+// Safe to call from multiple goroutines, but the order of sprites submitted
+// concurrently is undefined, use a CommandList per goroutine if order matters.
 func Render(sprites ...Sprite) {
+	Renderer.defaultLayer.Render(sprites...)
+}
+
+// CommandList records sprites on a single goroutine without taking any locks,
+// call Submit to queue them for the next frame. A CommandList must not be used
+// by multiple goroutines at the same time.
+type CommandList struct {
+	sprites map[*Layer][]Sprite
+}
+
+// records sprites into the default layer, they will be drawn in the order recorded
+func (l *CommandList) Render(sprites ...Sprite) {
+	l.RenderTo(Renderer.defaultLayer, sprites...)
+}
+
+// records sprites into the given layer, they will be drawn in the order recorded
+func (l *CommandList) RenderTo(layer *Layer, sprites ...Sprite) {
+	if l.sprites == nil {
+		l.sprites = make(map[*Layer][]Sprite)
+	}
+	l.sprites[layer] = appendVisible(l.sprites[layer], sprites)
+}
+
+// discards everything recorded so far
+func (l *CommandList) Reset() {
+	for layer, sprites := range l.sprites {
+		clear(sprites)
+		l.sprites[layer] = sprites[:0]
+	}
+}
+
+// queues the content of each list for the next frame in the order given and
+// resets the lists so that they can be reused
+func Submit(lists ...*CommandList) {
+	Renderer.submitLock.Lock()
+	for _, l := range lists {
+		for layer, sprites := range l.sprites {
+			layer.back = append(layer.back, sprites...)
+		}
+	}
+	Renderer.submitLock.Unlock()
+
+	for _, l := range lists {
+		l.Reset()
+	}
+}
+
+func appendVisible(dst []Sprite, sprites []Sprite) []Sprite {
 	for _, s := range sprites {
 		if s.texture != nil && s.Color[3] > 0 {
-			Renderer.sprites = append(Renderer.sprites, s)
+			dst = append(dst, s)
 		}
 	}
+	return dst
 }
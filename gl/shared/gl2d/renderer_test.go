@@ -0,0 +1,114 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gl2d
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestRenderConcurrent(t *testing.T) {
+	const (
+		numGoroutines = 8
+		numSprites    = 1000
+	)
+
+	s := Sprite{texture: &texture{}, Color: [4]float32{1, 1, 1, 1}}
+	hidden := Sprite{texture: &texture{}}
+
+	drawn := 0
+	done := make(chan struct{})
+	swapped := make(chan struct{})
+
+	// stands in for Draw, swapping frames while the submitters run
+	go func() {
+		defer close(swapped)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				Renderer.swapFrame()
+				drawn += len(Renderer.defaultLayer.front)
+			}
+		}
+	}()
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < numGoroutines; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < numSprites; j++ {
+				Render(s, hidden)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			l := CommandList{}
+			for j := 0; j < numSprites; j++ {
+				l.Render(s, hidden)
+				if j%10 == 9 {
+					Submit(&l)
+				}
+			}
+			Submit(&l)
+		}()
+	}
+
+	wg.Wait()
+	close(done)
+	<-swapped
+
+	Renderer.swapFrame()
+	drawn += len(Renderer.defaultLayer.front)
+	Renderer.swapFrame()
+
+	if want := numGoroutines * numSprites * 2; drawn != want {
+		t.Fatalf("Drew %d sprites, want %d", drawn, want)
+	}
+}
+
+func TestCommandListOrder(t *testing.T) {
+	a := Sprite{texture: &texture{filename: "a"}, Color: [4]float32{1, 1, 1, 1}}
+	b := Sprite{texture: &texture{filename: "b"}, Color: [4]float32{1, 1, 1, 1}}
+
+	l1 := CommandList{}
+	l2 := CommandList{}
+
+	l2.Render(b, a)
+	l1.Render(a, b)
+	Submit(&l1, &l2)
+
+	if len(l1.sprites[Renderer.defaultLayer]) != 0 || len(l2.sprites[Renderer.defaultLayer]) != 0 {
+		t.Fatal("Submit did not reset the lists")
+	}
+
+	Renderer.swapFrame()
+	got := ""
+	for _, s := range Renderer.defaultLayer.front {
+		got += s.texture.filename
+	}
+	Renderer.swapFrame()
+
+	if got != "abba" {
+		t.Fatalf("Drew %q, want %q", got, "abba")
+	}
+}
@@ -20,10 +20,9 @@ limitations under the License.
 package gl2d
 
 /*
-	#cgo linux LDFLAGS: -lGL -lGLU
-	#cgo windows LDFLAGS: -lopengl32 -lglu32
+	#cgo linux LDFLAGS: -lGL
+	#cgo windows LDFLAGS: -lopengl32
 	#include <GL/gl.h>
-	#include <GL/glu.h>
 */
 import "C"
 
@@ -40,11 +39,27 @@ import (
 
 type texture struct {
 	refs       *int64
-	id         C.GLuint
+	gl         *textureGL
 	filename   string
 	resolution gmath.Vector3int
+	// row major alpha of every texel, kept on the CPU for picking
+	alpha []uint8
 }
 
+/*
+textureGL is shared by every copy of a texture so that a re-upload after the
+context was recreated is seen by sprites already holding the texture, it must
+only be accessed on the render thread.
+*/
+type textureGL struct {
+	id C.GLuint
+	// the context generation id was created in, ids from older contexts are invalid
+	generation uint64
+}
+
+// uploads an image into g in the current context, tests replace it as they run without one
+var textureUpload = (*textureGL).upload
+
 func textureLoad(file string) (*texture, error) {
 	Renderer.textureLock.RLock()
 
@@ -66,6 +81,36 @@ func textureLoad(file string) (*texture, error) {
 		return &t, nil
 	}
 
+	img, err := textureDecode(file)
+	if err != nil {
+		return nil, err
+	}
+
+	t := texture{
+		refs:     new(int64),
+		gl:       &textureGL{},
+		filename: file,
+		resolution: gmath.Vector3int{
+			X: img.Bounds().Dx(),
+			Y: img.Bounds().Dy(),
+		},
+		alpha: alphaMask(img),
+	}
+
+	Renderer.runAsync(func() {
+		textureUpload(t.gl, t.filename, img)
+	})
+
+	(*t.refs) = 1
+	Renderer.textures[file] = t
+
+	runtime.SetFinalizer(&t, (*texture).close)
+	return &t, err
+}
+
+// the reload recipe for a texture, it is called again when the context is
+// recreated so the source image does not have to be kept around
+func textureDecode(file string) (image.Image, error) {
 	a, err := asset.Load(file)
 	if err != nil {
 		return nil, debug.ErrorWrapf(err, "Failed to load texture")
@@ -83,58 +128,112 @@ func textureLoad(file string) (*texture, error) {
 		return nil, debug.Errorf("Unsupported image format %T", t)
 	}
 
-	t := texture{
-		refs:     new(int64),
-		filename: a.Name(),
-		resolution: gmath.Vector3int{
-			X: img.Bounds().Dx(),
-			Y: img.Bounds().Dy(),
-		},
+	return img, nil
+}
+
+// creates the texture in the current context, replacing any previous upload
+// from the same context
+func (g *textureGL) upload(filename string, img image.Image) {
+	if g.id != 0 && g.generation == Renderer.glGeneration {
+		C.glDeleteTextures(1, &g.id)
 	}
+	g.id = 0
+	g.generation = Renderer.glGeneration
 
-	Renderer.runAsync(func() {
-		C.glGenTextures(1, &t.id)
-		C.glBindTexture(C.GL_TEXTURE_2D, t.id)
-
-		C.glTexParameterf(C.GL_TEXTURE_2D, C.GL_TEXTURE_MAG_FILTER, C.GL_LINEAR)
-		C.glTexParameterf(C.GL_TEXTURE_2D, C.GL_TEXTURE_MIN_FILTER, C.GL_LINEAR)
-
-		// C.glPixelStorei(C.GL_UNPACK_ALIGNMENT, rowAlign) // 1, 2, 4, 8
-
-		switch img := img.(type) {
-		case *image.RGBA:
-			C.glTexImage2D(C.GL_TEXTURE_2D, 0, C.GL_RGBA8,
-				C.int(img.Bounds().Dx()), C.int(img.Bounds().Dy()), 0, C.GL_RGBA,
-				C.GL_UNSIGNED_BYTE, unsafe.Pointer(&img.Pix[0]))
-		case *image.NRGBA:
-			C.glTexImage2D(C.GL_TEXTURE_2D, 0, C.GL_RGBA8,
-				C.int(img.Bounds().Dx()), C.int(img.Bounds().Dy()), 0, C.GL_RGBA,
-				C.GL_UNSIGNED_BYTE, unsafe.Pointer(&img.Pix[0]))
-		default:
-			C.glDeleteTextures(1, &t.id)
-		}
+	var id C.GLuint
+	C.glGenTextures(1, &id)
+	C.glBindTexture(C.GL_TEXTURE_2D, id)
+	glLabelTexture(id, filename)
+
+	C.glTexParameterf(C.GL_TEXTURE_2D, C.GL_TEXTURE_MAG_FILTER, C.GL_LINEAR)
+	C.glTexParameterf(C.GL_TEXTURE_2D, C.GL_TEXTURE_MIN_FILTER, C.GL_LINEAR)
+
+	// C.glPixelStorei(C.GL_UNPACK_ALIGNMENT, rowAlign) // 1, 2, 4, 8
+
+	switch img := img.(type) {
+	case *image.RGBA:
+		C.glTexImage2D(C.GL_TEXTURE_2D, 0, C.GL_RGBA8,
+			C.int(img.Bounds().Dx()), C.int(img.Bounds().Dy()), 0, C.GL_RGBA,
+			C.GL_UNSIGNED_BYTE, unsafe.Pointer(&img.Pix[0]))
+	case *image.NRGBA:
+		C.glTexImage2D(C.GL_TEXTURE_2D, 0, C.GL_RGBA8,
+			C.int(img.Bounds().Dx()), C.int(img.Bounds().Dy()), 0, C.GL_RGBA,
+			C.GL_UNSIGNED_BYTE, unsafe.Pointer(&img.Pix[0]))
+	}
+
+	C.glBindTexture(C.GL_TEXTURE_2D, 0)
+
+	if !glCheck("Upload texture " + filename) {
+		C.glDeleteTextures(1, &id)
+		return
+	}
 
-		glErr := C.glGetError()
+	g.id = id
+}
 
-		if glErr != C.GL_NO_ERROR {
-			for ; glErr != C.GL_NO_ERROR; glErr = C.glGetError() {
-				debug.EPrintf("Error during processing texture %s %v", t.filename, debug.Errorf(C.GoString((*C.char)(unsafe.Pointer(C.gluErrorString(glErr))))))
-			}
-			return
+// re-uploads every cached texture, called on the render thread after the
+// context was recreated as every id from the old context is now invalid.
+// Images are decoded from disk again rather than kept in memory, so this
+// blocks the render thread for the decode of every file.
+func (r *gl2d) textureReloadAll() {
+	r.textureLock.RLock()
+	textures := make(map[string]texture, len(r.textures))
+	for file, t := range r.textures {
+		textures[file] = t
+	}
+	r.textureLock.RUnlock()
+
+	for file, t := range textures {
+		if t.gl.generation == r.glGeneration {
+			continue
 		}
 
-		Renderer.textureLock.Lock()
-		if _, ok := Renderer.textures[t.filename]; ok {
-			Renderer.textures[file] = t
+		debug.IPrintf("Reloading texture %q", file)
+		img, err := textureDecode(file)
+		if err != nil {
+			debug.EPrintf("Failed to reload texture %q: %v", file, err)
+			t.gl.id = 0
+			continue
 		}
-		Renderer.textureLock.Unlock()
-	})
 
-	(*t.refs) = 1
-	Renderer.textures[file] = t
+		textureUpload(t.gl, t.filename, img)
+	}
+}
 
-	runtime.SetFinalizer(&t, (*texture).close)
-	return &t, err
+func alphaMask(img image.Image) []uint8 {
+	var pix []uint8
+	var stride int
+
+	switch img := img.(type) {
+	case *image.RGBA:
+		pix, stride = img.Pix, img.Stride
+	case *image.NRGBA:
+		pix, stride = img.Pix, img.Stride
+	default:
+		return nil
+	}
+
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	alpha := make([]uint8, 0, w*h)
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			alpha = append(alpha, pix[y*stride+x*4+3])
+		}
+	}
+
+	return alpha
+}
+
+// returns the alpha of the texel at x, y in the range [0, 1]
+func (t *texture) alphaAt(x, y int) float32 {
+	if t.alpha == nil {
+		return 1
+	}
+
+	x = min(max(x, 0), t.resolution.X-1)
+	y = min(max(y, 0), t.resolution.Y-1)
+	return float32(t.alpha[y*t.resolution.X+x]) / 255
 }
 
 func (t *texture) close() {
@@ -146,7 +245,10 @@ func (t *texture) close() {
 		if t, ok := Renderer.textures[t.filename]; ok {
 			debug.IPrintf("Deleting unused texture %q", t.filename)
 			Renderer.runAsync(func() {
-				C.glDeleteTextures(1, &t.id)
+				if t.gl.id != 0 && t.gl.generation == Renderer.glGeneration {
+					C.glDeleteTextures(1, &t.gl.id)
+				}
+				t.gl.id = 0
 			})
 			delete(Renderer.textures, t.filename)
 		}
@@ -0,0 +1,90 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gl2d
+
+import (
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"unsafe"
+)
+
+func TestTextureReload(t *testing.T) {
+	// stands in for GL by handing out increasing ids
+	nextID := 0
+	textureUpload = func(g *textureGL, _ string, _ image.Image) {
+		nextID++
+		// test files cannot use cgo types by name, GLuint is 32 bits
+		*(*uint32)(unsafe.Pointer(&g.id)) = uint32(nextID)
+		g.generation = Renderer.glGeneration
+	}
+	t.Cleanup(func() { textureUpload = (*textureGL).upload })
+
+	file := filepath.Join(t.TempDir(), "reload.png")
+	f, err := os.Create(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := png.Encode(f, image.NewNRGBA(image.Rect(0, 0, 2, 2))); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	Renderer.contextInit(nil)
+
+	tex, err := textureLoad(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		Renderer.textureLock.Lock()
+		delete(Renderer.textures, file)
+		Renderer.textureLock.Unlock()
+	})
+
+	// the first upload is queued for the render thread
+	select {
+	case job := <-Renderer.jobs:
+		job()
+	case <-time.After(time.Second):
+		t.Fatal("Upload was not queued")
+	}
+
+	first := tex.gl.id
+	if first == 0 || tex.gl.generation != Renderer.glGeneration {
+		t.Fatalf("Texture %+v not uploaded in generation %d", *tex.gl, Renderer.glGeneration)
+	}
+
+	// a new context re-uploads right away as GLInit runs on the render thread
+	Renderer.contextInit(nil)
+	if tex.gl.id == first || tex.gl.generation != Renderer.glGeneration {
+		t.Fatalf("Texture %+v not reloaded in generation %d", *tex.gl, Renderer.glGeneration)
+	}
+
+	// textures of the current context are left alone
+	reloaded := tex.gl.id
+	Renderer.textureReloadAll()
+	if tex.gl.id != reloaded {
+		t.Fatal("Texture of the current context was reloaded")
+	}
+}
@@ -0,0 +1,237 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mixer
+
+import (
+	"math"
+	"math/bits"
+	"sync"
+	"sync/atomic"
+
+	"goarrg.com/asset/audio"
+)
+
+const defaultAnalyzerSize = 2048
+
+/*
+Analyzer is an Effect that measures the spectrum of what passes through it
+without changing it, add it to a bus with Bus.AddEffect or to the master
+track with AddMasterEffect. The audio thread only copies samples, the FFT is
+done by Spectrum on the calling goroutine.
+*/
+type Analyzer struct {
+	size int
+	// a window is published every hop samples
+	hop       int
+	frequency atomic.Int64
+
+	// audio thread state
+	channels []audio.Channel
+	// the slices of the track being processed, looked up once per block
+	inputs   [][]float32
+	ring     []float32
+	pos      int
+	sinceHop int
+	windows  tripleBuffer
+
+	// Spectrum state
+	mtx    sync.Mutex
+	window []float64
+	re, im []float64
+}
+
+/*
+NewAnalyzer returns an analyzer with an FFT of size samples rounded up to a
+power of two, 0 uses 2048. Larger sizes resolve lower frequencies but react
+slower.
+*/
+func NewAnalyzer(size int) *Analyzer {
+	if size <= 0 {
+		size = defaultAnalyzerSize
+	}
+	size = 1 << bits.Len(uint(size-1))
+
+	a := &Analyzer{
+		size:   size,
+		hop:    size / 4,
+		ring:   make([]float32, size),
+		window: make([]float64, size),
+		re:     make([]float64, size),
+		im:     make([]float64, size),
+	}
+	a.windows.init(size)
+
+	// hann window, its coherent gain of 0.5 is undone in Spectrum
+	for i := range a.window {
+		a.window[i] = 0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/float64(size))
+	}
+
+	return a
+}
+
+func (a *Analyzer) Init(spec audio.Spec) {
+	a.channels = spec.Channels
+	a.inputs = make([][]float32, len(spec.Channels))
+	a.frequency.Store(int64(spec.Frequency))
+}
+
+// copies the downmixed samples into the window, the track is not changed
+func (a *Analyzer) Process(track audio.Track, samples int) {
+	if len(a.channels) == 0 {
+		return
+	}
+	scale := 1 / float32(len(a.channels))
+
+	for ch, c := range a.channels {
+		a.inputs[ch] = track[c][:samples]
+	}
+
+	for i := 0; i < samples; i++ {
+		sum := float32(0)
+		for _, in := range a.inputs {
+			sum += in[i]
+		}
+
+		a.ring[a.pos] = sum * scale
+		a.pos = (a.pos + 1) % a.size
+
+		if a.sinceHop++; a.sinceHop == a.hop {
+			a.sinceHop = 0
+
+			// oldest sample first
+			back := a.windows.back()
+			n := copy(back, a.ring[a.pos:])
+			copy(back[n:], a.ring[:a.pos])
+			a.windows.publish()
+		}
+	}
+}
+
+// returns the number of bins returned by Spectrum
+func (a *Analyzer) Bins() int {
+	return a.size / 2
+}
+
+// returns the center frequency in Hz of a bin returned by Spectrum
+func (a *Analyzer) BinFrequency(bin int) float64 {
+	return float64(bin) * float64(a.frequency.Load()) / float64(a.size)
+}
+
+/*
+Spectrum appends the linear amplitude of every frequency bin of the latest
+window to dst and returns it, a full scale sine centered on a bin reads 1.
+It never blocks the audio thread, before the first window is complete every
+bin is 0.
+*/
+func (a *Analyzer) Spectrum(dst []float32) []float32 {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	samples := a.windows.front()
+	for i, s := range samples {
+		a.re[i] = float64(s) * a.window[i]
+		a.im[i] = 0
+	}
+
+	fft(a.re, a.im)
+
+	// one sided spectrum of a windowed signal, DC has no negative frequency
+	scale := 2 / (0.5 * float64(a.size))
+	dst = append(dst, float32(math.Abs(a.re[0])*scale/2))
+	for i := 1; i < a.Bins(); i++ {
+		dst = append(dst, float32(math.Hypot(a.re[i], a.im[i])*scale))
+	}
+
+	return dst
+}
+
+/*
+tripleBuffer hands buffers from one writer to one reader without either
+waiting, the writer fills back while the reader holds front and middle holds
+the latest complete buffer.
+*/
+type tripleBuffer struct {
+	bufs [3][]float32
+	// writer's buffer index
+	backIndex int
+	// reader's buffer index
+	frontIndex int
+	// index of the middle buffer, or'ed with tripleBufferFresh once written
+	middle atomic.Uint32
+}
+
+const tripleBufferFresh = 4
+
+func (t *tripleBuffer) init(size int) {
+	for i := range t.bufs {
+		t.bufs[i] = make([]float32, size)
+	}
+	t.backIndex, t.frontIndex = 0, 1
+	t.middle.Store(2)
+}
+
+// returns the buffer the writer fills next
+func (t *tripleBuffer) back() []float32 {
+	return t.bufs[t.backIndex]
+}
+
+// makes the back buffer the latest one
+func (t *tripleBuffer) publish() {
+	old := t.middle.Swap(uint32(t.backIndex) | tripleBufferFresh)
+	t.backIndex = int(old &^ tripleBufferFresh)
+}
+
+// returns the latest complete buffer, it stays valid until the next call
+func (t *tripleBuffer) front() []float32 {
+	if t.middle.Load()&tripleBufferFresh != 0 {
+		old := t.middle.Swap(uint32(t.frontIndex))
+		t.frontIndex = int(old &^ tripleBufferFresh)
+	}
+	return t.bufs[t.frontIndex]
+}
+
+// fft computes the discrete fourier transform of re and im in place, their
+// length must be a power of two
+func fft(re, im []float64) {
+	n := len(re)
+	shift := bits.UintSize - bits.Len(uint(n-1))
+
+	for i := 0; i < n; i++ {
+		if j := int(bits.Reverse(uint(i)) >> shift); j > i {
+			re[i], re[j] = re[j], re[i]
+			im[i], im[j] = im[j], im[i]
+		}
+	}
+
+	for size := 2; size <= n; size *= 2 {
+		step := -2 * math.Pi / float64(size)
+		for start := 0; start < n; start += size {
+			for k := 0; k < size/2; k++ {
+				sin, cos := math.Sincos(step * float64(k))
+				i, j := start+k, start+k+size/2
+
+				tre := re[j]*cos - im[j]*sin
+				tim := re[j]*sin + im[j]*cos
+				re[j], im[j] = re[i]-tre, im[i]-tim
+				re[i], im[i] = re[i]+tre, im[i]+tim
+			}
+		}
+	}
+}
@@ -0,0 +1,129 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mixer
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"goarrg.com/asset/audio"
+)
+
+func TestFFT(t *testing.T) {
+	const n = 16
+
+	re := make([]float64, n)
+	im := make([]float64, n)
+	signal := make([]complex128, n)
+	for i := range signal {
+		signal[i] = complex(math.Sin(float64(i)), math.Cos(float64(i*i)))
+		re[i], im[i] = real(signal[i]), imag(signal[i])
+	}
+
+	fft(re, im)
+
+	// compare with the definition
+	for k := range n {
+		want := complex128(0)
+		for i, x := range signal {
+			want += x * cmplx.Exp(complex(0, -2*math.Pi*float64(k*i)/n))
+		}
+		if cmplx.Abs(want-complex(re[k], im[k])) > 1e-9 {
+			t.Fatalf("Bin %d = %v, want %v", k, complex(re[k], im[k]), want)
+		}
+	}
+}
+
+func TestAnalyzer(t *testing.T) {
+	a := newTestMixer()
+	analyzer := NewAnalyzer(1000)
+	if analyzer.Bins() != 512 {
+		t.Fatalf("%d bins, want 512", analyzer.Bins())
+	}
+
+	a.categoryBuses[CategorySFX].AddEffect(analyzer)
+	// effects are initialized by the audio thread
+	a.commands.drain()
+
+	if s := analyzer.Spectrum(nil); len(s) != 512 || s[10] != 0 {
+		t.Fatal("Spectrum before the first window was not silent")
+	}
+
+	// a sine centered on bin 20 at half scale
+	hz := analyzer.BinFrequency(20)
+	sine := make(audio.Track)
+	for _, c := range audio.ChannelsStereo() {
+		sine[c] = make([]float32, testFrequency)
+		for i := range sine[c] {
+			sine[c][i] = 0.5 * float32(math.Sin(2*math.Pi*hz*float64(i)/testFrequency))
+		}
+	}
+	a.play(newTrackAsset(audio.Spec{Channels: audio.ChannelsStereo(), Frequency: testFrequency}, sine), PlayConfig{})
+	a.mix(a.bufferSamples)
+
+	if math.Abs(hz-20*testFrequency/1024.0) > 1e-9 {
+		t.Fatalf("Bin 20 at %f Hz", hz)
+	}
+
+	spectrum := analyzer.Spectrum(nil)
+	assertNear(t, "bin 20", spectrum[20], 0.5)
+	for i, m := range spectrum {
+		// the hann window leaks into the neighbouring bins only
+		if (i < 19 || i > 21) && m > 0.001 {
+			t.Fatalf("Bin %d = %f", i, m)
+		}
+	}
+
+	// the buffers are reused
+	if s := analyzer.Spectrum(spectrum[:0]); &s[0] != &spectrum[0] {
+		t.Fatal("Spectrum did not append to dst")
+	}
+}
+
+func TestAnalyzerConcurrent(t *testing.T) {
+	a := newTestMixer()
+	analyzer := NewAnalyzer(256)
+	a.categoryBuses[CategorySFX].AddEffect(analyzer)
+
+	v := a.play(constantAsset(0.5, 1000), PlayConfig{})
+	v.SetLoop(LoopForever)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 200 {
+			a.mix(100)
+		}
+	}()
+
+	var spectrum []float32
+	for {
+		select {
+		case <-done:
+			// a constant signal only has a DC component
+			spectrum = analyzer.Spectrum(spectrum[:0])
+			assertNear(t, "dc", spectrum[0], 0.5)
+			return
+		default:
+			spectrum = analyzer.Spectrum(spectrum[:0])
+		}
+	}
+}
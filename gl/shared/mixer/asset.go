@@ -0,0 +1,58 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mixer
+
+import (
+	"goarrg.com/asset/audio"
+)
+
+// trackAsset is an audio.Asset for tracks generated or converted by the mixer
+type trackAsset struct {
+	spec    audio.Spec
+	track   audio.Track
+	samples int
+}
+
+var _ audio.Asset = (*trackAsset)(nil)
+
+func newTrackAsset(spec audio.Spec, track audio.Track) *trackAsset {
+	samples := 0
+	if len(spec.Channels) > 0 {
+		samples = len(track[spec.Channels[0]])
+	}
+
+	return &trackAsset{spec: spec, track: track, samples: samples}
+}
+
+func (t *trackAsset) Track() audio.Track {
+	return t.track
+}
+
+func (t *trackAsset) Spec() audio.Spec {
+	return t.spec
+}
+
+func (t *trackAsset) DurationSeconds() float64 {
+	return float64(t.samples) / float64(t.spec.Frequency)
+}
+
+func (t *trackAsset) DurationSamples() int {
+	return t.samples
+}
@@ -0,0 +1,266 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mixer
+
+import (
+	"math"
+	"slices"
+	"sync"
+)
+
+// beats are reported this far ahead of the buffered audio so sounds played
+// with PlayAt on a reported beat are not late, it covers a frame between Updates
+const beatLookaheadSeconds = 0.1
+
+type TimeSignature struct {
+	// beats in a bar
+	Beats int
+	// note value of a beat, 4 for quarter notes and 8 for eighth notes
+	Unit int
+}
+
+type Beat struct {
+	// beats since the clock started
+	Index int
+	Bar   int
+	// beat within the bar, 0 is the downbeat
+	InBar int
+	// mixer sample time the beat falls on, pass it to PlayAt to play on the beat
+	Time int64
+}
+
+/*
+BeatClock divides the mixer's sample clock into beats and bars, beat times are
+exact output samples so sounds played on them with PlayAt line up regardless
+of when the game calls Update.
+*/
+type BeatClock struct {
+	mtx   sync.Mutex
+	mixer *audioMixer
+	sig   TimeSignature
+	// tempo changes start a new segment at a beat so earlier beats keep their
+	// time, never empty and ordered by beat
+	segments []tempoSegment
+	// index of the next beat to report
+	next   int
+	onBeat func(Beat)
+	onBar  func(Beat)
+}
+
+// the tempo from a beat on
+type tempoSegment struct {
+	beat int
+	time int64
+	bpm  float64
+}
+
+/*
+NewBeatClock starts a clock with beat 0 on mixer sample time start, use
+SampleTime for a clock starting with the next mixed sample. BPM counts quarter
+notes like most sequencers, a time signature of 6/8 at 120 BPM has 4 beats a
+second. The clock reports beats to its callbacks from Update until stopped.
+*/
+func NewBeatClock(bpm float64, sig TimeSignature, start int64) *BeatClock {
+	return newBeatClock(Mixer, bpm, sig, start)
+}
+
+func newBeatClock(a *audioMixer, bpm float64, sig TimeSignature, start int64) *BeatClock {
+	c := &BeatClock{
+		mixer:    a,
+		sig:      TimeSignature{Beats: max(sig.Beats, 1), Unit: max(sig.Unit, 1)},
+		segments: []tempoSegment{{time: start, bpm: max(bpm, 1)}},
+	}
+	// beats already mixed are not reported
+	c.next = max(c.beatAfter(a.published.clock.Load()), 0)
+
+	a.mtx.Lock()
+	a.clocks = append(a.clocks, c)
+	a.mtx.Unlock()
+
+	return c
+}
+
+// stops reporting beats, the clock can still be used to compute beat times
+func (c *BeatClock) Stop() {
+	a := c.mixer
+
+	a.mtx.Lock()
+	a.clocks = slices.DeleteFunc(a.clocks, func(other *BeatClock) bool { return other == c })
+	a.mtx.Unlock()
+}
+
+// sets the function called on the game thread for every beat, nil removes it
+func (c *BeatClock) OnBeat(f func(Beat)) {
+	c.mtx.Lock()
+	c.onBeat = f
+	c.mtx.Unlock()
+}
+
+// sets the function called on the game thread for the first beat of every bar
+// after OnBeat, nil removes it
+func (c *BeatClock) OnBar(f func(Beat)) {
+	c.mtx.Lock()
+	c.onBar = f
+	c.mtx.Unlock()
+}
+
+// changes the tempo from the next beat not reported yet, earlier beats keep
+// their time so sounds already scheduled on them stay in time
+func (c *BeatClock) SetTempo(bpm float64) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	next := max(c.next, c.beatAfter(c.mixer.published.clock.Load()))
+	segment := tempoSegment{beat: next, time: c.beatTime(next), bpm: max(bpm, 1)}
+
+	// segments past the new one never started
+	i := len(c.segments)
+	for i > 1 && c.segments[i-1].beat >= next {
+		i--
+	}
+	c.segments = append(c.segments[:i], segment)
+}
+
+func (c *BeatClock) Tempo() float64 {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.segments[len(c.segments)-1].bpm
+}
+
+// returns the beat with the given index since the clock started
+func (c *BeatClock) Beat(index int) Beat {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.beat(index)
+}
+
+// returns the first beat at or after the next mixed sample
+func (c *BeatClock) NextBeat() Beat {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.beat(c.beatAfter(c.mixer.published.clock.Load()))
+}
+
+// returns the first downbeat at or after the next mixed sample
+func (c *BeatClock) NextBar() Beat {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	index := c.beatAfter(c.mixer.published.clock.Load())
+	if r := mod(index, c.sig.Beats); r != 0 {
+		index += c.sig.Beats - r
+	}
+	return c.beat(index)
+}
+
+// must be called with mtx held
+func (c *BeatClock) beat(index int) Beat {
+	return Beat{
+		Index: index,
+		Bar:   floorDiv(index, c.sig.Beats),
+		InBar: mod(index, c.sig.Beats),
+		Time:  c.beatTime(index),
+	}
+}
+
+// returns the samples between beats at the output frequency, must be called with mtx held
+func (c *BeatClock) beatSamples(bpm float64) float64 {
+	quarter := 60 / bpm * float64(c.mixer.outputSpec().Frequency)
+	return quarter * 4 / float64(c.sig.Unit)
+}
+
+// returns the segment the beat falls in, the first one for beats before the clock started
+func (c *BeatClock) segmentOfBeat(index int) tempoSegment {
+	for i := len(c.segments) - 1; i > 0; i-- {
+		if c.segments[i].beat <= index {
+			return c.segments[i]
+		}
+	}
+	return c.segments[0]
+}
+
+// returns the segment playing at sample time t
+func (c *BeatClock) segmentAt(t int64) tempoSegment {
+	for i := len(c.segments) - 1; i > 0; i-- {
+		if c.segments[i].time <= t {
+			return c.segments[i]
+		}
+	}
+	return c.segments[0]
+}
+
+// must be called with mtx held
+func (c *BeatClock) beatTime(index int) int64 {
+	s := c.segmentOfBeat(index)
+	return s.time + int64(math.Round(float64(index-s.beat)*c.beatSamples(s.bpm)))
+}
+
+// returns the index of the first beat at or after sample time t, must be called with mtx held
+func (c *BeatClock) beatAfter(t int64) int {
+	s := c.segmentAt(t)
+	index := s.beat + int(math.Ceil(float64(t-s.time)/c.beatSamples(s.bpm)))
+	// rounding the beat times can put a beat one sample off the estimate
+	for c.beatTime(index) < t {
+		index++
+	}
+	for c.beatTime(index-1) >= t {
+		index--
+	}
+	return index
+}
+
+// reports the beats coming up before the next block is mixed, called by Update.
+// The next block ends up to the latency target past the mixed audio, which
+// grows with underruns.
+func (c *BeatClock) update() {
+	a := c.mixer
+	horizon := a.published.clock.Load() + a.published.latency.target.Load() + int64(a.seconds(beatLookaheadSeconds))
+
+	c.mtx.Lock()
+	var beats []Beat
+	for ; c.beatTime(c.next) < horizon; c.next++ {
+		beats = append(beats, c.beat(c.next))
+	}
+	onBeat, onBar := c.onBeat, c.onBar
+	c.mtx.Unlock()
+
+	// called without mtx so the callbacks can use the clock
+	for _, b := range beats {
+		if onBeat != nil {
+			onBeat(b)
+		}
+		if onBar != nil && b.InBar == 0 {
+			onBar(b)
+		}
+	}
+}
+
+// rounds towards negative infinity unlike /
+func floorDiv(a, b int) int {
+	return (a - mod(a, b)) / b
+}
+
+// returns a positive remainder unlike %
+func mod(a, b int) int {
+	return ((a % b) + b) % b
+}
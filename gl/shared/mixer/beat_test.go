@@ -0,0 +1,198 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mixer
+
+import (
+	"math"
+	"testing"
+
+	"goarrg.com/asset/audio"
+)
+
+func TestPlayAt(t *testing.T) {
+	a := newTestMixer()
+
+	a.play(constantAsset(0.5, 100), PlayConfig{StartAt: 1234})
+	a.mix(2000)
+
+	left := a.masterTrack[audio.ChannelLeft]
+	assertNear(t, "before", left[1233], 0)
+	assertNear(t, "start", left[1234], 0.5)
+	assertNear(t, "end", left[1333], 0.5)
+	assertNear(t, "after", left[1334], 0)
+
+	// a time already mixed starts right away
+	a.play(constantAsset(0.5, 100), PlayConfig{StartAt: 10})
+	a.mix(10)
+	assertNear(t, "late", a.masterTrack[audio.ChannelLeft][0], 0.5)
+}
+
+func TestPlayAtInsideBlocks(t *testing.T) {
+	a := newTestMixer()
+	starts := []int64{100, 1700, 2500, 4000, 9000}
+	for _, start := range starts {
+		a.play(constantAsset(0.25, 50), PlayConfig{StartAt: start})
+	}
+
+	// blocks of changing size so starts fall part way into small and large blocks
+	var left []float32
+	for i, block := 0, 0; len(left) < 10000; i++ {
+		block = []int{300, a.bufferSamples, 1000, 64}[i%4]
+		a.mix(block)
+		left = append(left, a.masterTrack[audio.ChannelLeft][:block]...)
+	}
+
+	for _, start := range starts {
+		assertNear(t, "before", left[start-1], 0)
+		assertNear(t, "start", left[start], 0.25)
+		assertNear(t, "end", left[start+49], 0.25)
+		assertNear(t, "after", left[start+50], 0)
+	}
+}
+
+func TestBeatClock(t *testing.T) {
+	a := newTestMixer()
+
+	tests := []struct {
+		bpm   float64
+		sig   TimeSignature
+		index int
+		want  Beat
+	}{
+		{120, TimeSignature{4, 4}, 0, Beat{0, 0, 0, 1000}},
+		{120, TimeSignature{4, 4}, 5, Beat{5, 1, 1, 1000 + 5*22050}},
+		{120, TimeSignature{3, 4}, 7, Beat{7, 2, 1, 1000 + 7*22050}},
+		// eighth notes are half as long
+		{120, TimeSignature{6, 8}, 6, Beat{6, 1, 0, 1000 + 6*11025}},
+		{120, TimeSignature{4, 4}, -1, Beat{-1, -1, 3, 1000 - 22050}},
+	}
+
+	for _, test := range tests {
+		c := newBeatClock(a, test.bpm, test.sig, 1000)
+		if got := c.Beat(test.index); got != test.want {
+			t.Fatalf("%v %v beat %d = %+v, want %+v", test.bpm, test.sig, test.index, got, test.want)
+		}
+		c.Stop()
+	}
+
+	// beats fall on the nearest sample without drifting
+	c := newBeatClock(a, 130, TimeSignature{4, 4}, 0)
+	exact := 60.0 / 130 * testFrequency
+	for _, i := range []int{1, 7, 1000, 123456} {
+		if d := math.Abs(float64(c.Beat(i).Time) - float64(i)*exact); d > 0.5 {
+			t.Fatalf("Beat %d is %f samples off", i, d)
+		}
+	}
+	c.Stop()
+
+	if len(a.clocks) != 0 {
+		t.Fatalf("%d clocks left after Stop", len(a.clocks))
+	}
+}
+
+func TestBeatCallbacks(t *testing.T) {
+	a := newTestMixer()
+	// a beat every 0.1 seconds, 4 to a bar
+	c := newBeatClock(a, 600, TimeSignature{4, 4}, 100)
+
+	var beats, bars []Beat
+	c.OnBeat(func(b Beat) {
+		if now := a.published.clock.Load(); b.Time < now {
+			t.Fatalf("Beat %d at %d reported after mixing up to %d", b.Index, b.Time, now)
+		}
+		beats = append(beats, b)
+	})
+	c.OnBar(func(b Beat) {
+		bars = append(bars, b)
+		a.play(constantAsset(1, 10), PlayConfig{StartAt: b.Time})
+	})
+
+	out := render(a, testFrequency)
+
+	// beats up to the lookahead past the end are reported once in order
+	if len(beats) != 12 || len(bars) != 3 {
+		t.Fatalf("Reported %d beats and %d bars, want 12 and 3", len(beats), len(bars))
+	}
+	for i, b := range beats {
+		if b.Index != i {
+			t.Fatalf("Beat %d reported as %d", i, b.Index)
+		}
+	}
+
+	// the clicks played from OnBar start exactly on the downbeats
+	for _, b := range bars {
+		if b.Time >= int64(len(out)) {
+			continue
+		}
+		assertNear(t, "before bar", out[b.Time-1], 0)
+		assertNear(t, "bar", out[b.Time], 1)
+	}
+}
+
+func TestBeatLookahead(t *testing.T) {
+	a := newTestMixer()
+	// a beat every 0.1 seconds
+	c := newBeatClock(a, 600, TimeSignature{4, 4}, 100)
+
+	reported := 0
+	c.OnBeat(func(Beat) { reported++ })
+
+	// the default 0.1s target plus the lookahead
+	c.update()
+	if reported != 2 {
+		t.Fatalf("Reported %d beats, want 2", reported)
+	}
+
+	// a buffer grown by underruns mixes further ahead so beats are reported earlier
+	a.published.latency.target.Store(int64(a.seconds(0.5)))
+	c.update()
+	if reported != 6 {
+		t.Fatalf("Reported %d beats with a 0.5s target, want 6", reported)
+	}
+}
+
+func TestBeatClockTempo(t *testing.T) {
+	a := newTestMixer()
+	c := newBeatClock(a, 120, TimeSignature{4, 4}, 0)
+
+	var beats []Beat
+	c.OnBeat(func(b Beat) { beats = append(beats, b) })
+
+	render(a, testFrequency)
+	reported := c.Beat(len(beats) - 1)
+
+	c.SetTempo(240)
+	if c.Tempo() != 240 {
+		t.Fatalf("Tempo %f", c.Tempo())
+	}
+
+	// reported beats keep their time, later ones are twice as close
+	if got := c.Beat(reported.Index); got != reported {
+		t.Fatalf("Reported beat moved from %+v to %+v", reported, got)
+	}
+	if d := c.Beat(reported.Index+3).Time - c.Beat(reported.Index+2).Time; d != 11025 {
+		t.Fatalf("%d samples between beats at 240 BPM", d)
+	}
+
+	next := c.NextBar()
+	if next.InBar != 0 || next.Time < a.published.clock.Load() {
+		t.Fatalf("Next bar %+v at %d", next, a.published.clock.Load())
+	}
+}
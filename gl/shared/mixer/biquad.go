@@ -0,0 +1,132 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mixer
+
+import (
+	"math"
+
+	"goarrg.com/asset/audio"
+)
+
+type FilterKind uint8
+
+const (
+	LowPass FilterKind = iota
+	HighPass
+	// constant 0dB peak gain at the cutoff
+	BandPass
+)
+
+/*
+Biquad is a second order filter using the coefficients of the Audio EQ
+Cookbook, cutoff and Q changes are smoothed and the coefficients recomputed
+every sample while they ramp.
+*/
+type Biquad struct {
+	kind      FilterKind
+	cutoff    param
+	q         param
+	frequency float64
+	channels  []audio.Channel
+
+	b0, b1, b2, a1, a2 float64
+	// x1, x2, y1, y2 of every channel
+	state [][4]float64
+	// the slices of the track being processed, looked up once per block
+	inputs [][]float32
+}
+
+// q of 1/sqrt(2) gives a low or high pass without resonance
+func NewBiquad(kind FilterKind, cutoff, q float32) *Biquad {
+	f := &Biquad{kind: kind}
+	f.cutoff.init(cutoff)
+	f.q.init(q)
+	return f
+}
+
+// sets the cutoff, or center for band pass, in Hz
+func (f *Biquad) SetCutoff(hz float32) {
+	f.cutoff.set(hz)
+}
+
+func (f *Biquad) SetQ(q float32) {
+	f.q.set(q)
+}
+
+func (f *Biquad) Init(spec audio.Spec) {
+	f.frequency = float64(spec.Frequency)
+	f.channels = spec.Channels
+	f.state = make([][4]float64, len(spec.Channels))
+	f.inputs = make([][]float32, len(spec.Channels))
+
+	samples := automationSamples(spec.Frequency)
+	f.cutoff.samples = samples
+	f.q.samples = samples
+	f.cutoff.ramp = newRamp(f.cutoff.target())
+	f.q.ramp = newRamp(f.q.target())
+	f.coefficients(f.cutoff.ramp.value, f.q.ramp.value)
+}
+
+func (f *Biquad) Process(track audio.Track, samples int) {
+	f.cutoff.update()
+	f.q.update()
+
+	for c, ch := range f.channels {
+		f.inputs[c] = track[ch][:samples]
+	}
+
+	for i := 0; i < samples; i++ {
+		if f.cutoff.ramping() || f.q.ramping() {
+			f.coefficients(f.cutoff.next(), f.q.next())
+		}
+
+		for c, in := range f.inputs {
+			s := &f.state[c]
+			x := float64(in[i])
+			y := f.b0*x + f.b1*s[0] + f.b2*s[1] - f.a1*s[2] - f.a2*s[3]
+
+			s[1], s[0] = s[0], x
+			s[3], s[2] = s[2], y
+			in[i] = float32(y)
+		}
+	}
+}
+
+func (f *Biquad) coefficients(cutoff, q float32) {
+	// keep the filter stable for any input
+	hz := min(max(float64(cutoff), 1), f.frequency*0.499)
+	w0 := 2 * math.Pi * hz / f.frequency
+	cos := math.Cos(w0)
+	alpha := math.Sin(w0) / (2 * max(float64(q), 0.01))
+
+	var b0, b1, b2 float64
+	switch f.kind {
+	case HighPass:
+		b0, b1, b2 = (1+cos)/2, -(1 + cos), (1+cos)/2
+	case BandPass:
+		b0, b1, b2 = alpha, 0, -alpha
+	default:
+		b0, b1, b2 = (1-cos)/2, 1-cos, (1-cos)/2
+	}
+
+	a0 := 1 + alpha
+	f.b0, f.b1, f.b2 = b0/a0, b1/a0, b2/a0
+	f.a1, f.a2 = -2*cos/a0, (1-alpha)/a0
+}
@@ -0,0 +1,106 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mixer
+
+import (
+	"goarrg.com/asset/audio"
+)
+
+/*
+The mixer works on blocks of contiguous samples of one channel at a time, the
+loops below are kept free of branches and map lookups and reslice their
+arguments to the length of dst so the compiler drops the bounds checks.
+*/
+
+// scratch blocks the audio thread computes gains and samples in
+type blocks struct {
+	gain  []float32
+	left  []float32
+	right []float32
+	route []float32
+	level []float32
+}
+
+func (b *blocks) init(samples int) {
+	b.gain = make([]float32, samples)
+	b.left = make([]float32, samples)
+	b.right = make([]float32, samples)
+	b.route = make([]float32, samples)
+	b.level = make([]float32, samples)
+}
+
+// sets dst to src times gains times scale
+func scaleBlock(dst, src, gains []float32, scale float32) {
+	src = src[:len(dst)]
+	gains = gains[:len(dst)]
+
+	for i := range dst {
+		dst[i] = src[i] * gains[i] * scale
+	}
+}
+
+// multiplies dst by gains
+func mulBlock(dst, gains []float32) {
+	gains = gains[:len(dst)]
+
+	for i := range dst {
+		dst[i] *= gains[i]
+	}
+}
+
+// adds src to dst
+func addBlock(dst, src []float32) {
+	src = src[:len(dst)]
+
+	for i := range dst {
+		dst[i] += src[i]
+	}
+}
+
+// returns the first samples of buf, growing it if configure did not make it large enough
+func scratchBlock(buf *[]float32, samples int) []float32 {
+	if len(*buf) < samples {
+		*buf = make([]float32, samples)
+	}
+	return (*buf)[:samples]
+}
+
+// sets levels to the largest magnitude of every frame across the channels of track
+func linkedPeaks(levels []float32, track audio.Track, channels []audio.Channel) {
+	clear(levels)
+	for _, c := range channels {
+		peakBlock(levels, track[c])
+	}
+}
+
+// raises every level to the magnitude of the sample in src
+func peakBlock(levels, src []float32) {
+	src = src[:len(levels)]
+
+	for i := range levels {
+		s := src[i]
+		if s < 0 {
+			s = -s
+		}
+		if s > levels[i] {
+			levels[i] = s
+		}
+	}
+}
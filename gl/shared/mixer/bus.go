@@ -0,0 +1,410 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mixer
+
+import (
+	"math"
+	"slices"
+	"sync/atomic"
+
+	"goarrg.com/asset/audio"
+	"goarrg.com/debug"
+)
+
+// names of the buses every category plays on by default
+var categoryBusNames = [categoryCount]string{"sfx", "music", "ui", "voice"}
+
+/*
+Bus is a submix that voices and other buses play into, it has its own gain,
+effects and mute/solo and is routed into its parent bus or the master track.
+*/
+type Bus struct {
+	mixer *audioMixer
+	name  string
+	// nil routes into the master track
+	parent *Bus
+	// the bus ducking this one, guarded by mixer.mtx
+	sidechain *Bus
+	// gain the bus was ducked to in the last block as float32 bits
+	ducking atomic.Uint32
+
+	// everything below is only touched by the audio thread once the bus is ordered
+	gain ramp
+	mute bool
+	solo bool
+	// set while the bus or one of the buses routed into it is soloed
+	soloed bool
+	// ramps to 0 while muted or silenced by another bus' solo
+	audible ramp
+	effects []Effect
+	track   audio.Track
+	duck    *ducker
+	meter   meter
+}
+
+type DuckConfig struct {
+	// gain the bus is lowered to while the sidechain is active
+	Gain float32
+	// linear peak level above which the sidechain counts as active
+	Threshold float32
+	// seconds to lower and restore the gain
+	Attack  float64
+	Release float64
+}
+
+// ducker lowers a bus while its sidechain bus is active
+type ducker struct {
+	cfg       DuckConfig
+	sidechain *Bus
+	attack    float32
+	release   float32
+	gain      float32
+}
+
+func (d *ducker) configure(frequency int) {
+	d.attack = onePole(d.cfg.Attack, frequency)
+	d.release = onePole(d.cfg.Release, frequency)
+}
+
+// multiplies gains by the ducking gain following the peaks of the sidechain's
+// final output, levels is scratch space of the same length
+func (d *ducker) mul(gains, levels []float32, channels []audio.Channel) {
+	linkedPeaks(levels, d.sidechain.track, channels)
+
+	for i, level := range levels {
+		target, coef := float32(1), d.release
+		if level > d.cfg.Threshold {
+			target = d.cfg.Gain
+		}
+		if target < d.gain {
+			coef = d.attack
+		}
+
+		d.gain += (target - d.gain) * coef
+		gains[i] *= d.gain
+	}
+}
+
+// creates the default buses of the categories, must be called with mtx held
+func (a *audioMixer) defaultBuses() {
+	if a.categoryBuses[0] != nil {
+		return
+	}
+
+	for c, name := range categoryBusNames {
+		a.categoryBuses[c] = a.newBus(name, nil)
+	}
+}
+
+// must be called with mtx held
+func (a *audioMixer) newBus(name string, parent *Bus) *Bus {
+	b := &Bus{
+		mixer:   a,
+		name:    name,
+		parent:  parent,
+		gain:    newRamp(1),
+		audible: newRamp(1),
+	}
+	b.ducking.Store(math.Float32bits(1))
+
+	if a.spec.Frequency > 0 {
+		b.init(a.spec, a.bufferSamples)
+	}
+
+	a.buses = append(a.buses, b)
+	// cannot fail as a new bus only depends on its parent
+	_ = a.sortBuses()
+
+	return b
+}
+
+func (b *Bus) init(spec audio.Spec, samples int) {
+	b.track = make(audio.Track)
+	for _, c := range spec.Channels {
+		b.track[c] = make([]float32, samples)
+	}
+
+	for _, e := range b.effects {
+		e.Init(spec)
+	}
+}
+
+// returns the bus voices of the category play on unless PlayConfig.Bus is set
+func (a *audioMixer) categoryBus(c Category) *Bus {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	a.defaultBuses()
+	return a.categoryBuses[c]
+}
+
+/*
+orders the buses so every bus is processed after the buses routed into it and
+after the bus ducking it and passes the order on to the audio thread, returns
+an error if the routing has a cycle. Must be called with mtx held.
+*/
+func (a *audioMixer) sortBuses() error {
+	const (
+		visiting = iota + 1
+		visited
+	)
+
+	state := make(map[*Bus]int, len(a.buses))
+	order := make([]*Bus, 0, len(a.buses))
+
+	var visit func(b *Bus) error
+	visit = func(b *Bus) error {
+		switch state[b] {
+		case visiting:
+			return debug.Errorf("Bus %q is routed into itself", b.name)
+		case visited:
+			return nil
+		}
+
+		state[b] = visiting
+
+		for _, child := range a.buses {
+			if child.parent == b {
+				if err := visit(child); err != nil {
+					return err
+				}
+			}
+		}
+		if b.sidechain != nil {
+			if err := visit(b.sidechain); err != nil {
+				return err
+			}
+		}
+
+		state[b] = visited
+		order = append(order, b)
+		return nil
+	}
+
+	for _, b := range a.buses {
+		if err := visit(b); err != nil {
+			return err
+		}
+	}
+
+	a.commands.push(func() { a.busOrder = order })
+	return nil
+}
+
+// updates the audible ramp of every bus from mute and solo, only called by the audio thread
+func (a *audioMixer) updateSolo() {
+	// a soloed bus keeps its parents and children playing
+	for _, b := range a.busOrder {
+		b.soloed = false
+	}
+	anySolo := false
+	for _, b := range a.busOrder {
+		if !b.solo {
+			continue
+		}
+		anySolo = true
+		for p := b; p != nil && !p.soloed; p = p.parent {
+			p.soloed = true
+		}
+	}
+
+	for _, b := range a.busOrder {
+		audible := !anySolo || b.soloed
+		for p := b.parent; p != nil && !audible; p = p.parent {
+			audible = p.solo
+		}
+		audible = audible && !b.mute
+
+		gain := float32(0)
+		if audible {
+			gain = 1
+		}
+		if gain != b.audible.target {
+			b.audible.set(gain, a.rampSamples)
+		}
+	}
+}
+
+/*
+runs the bus' effects, applies its gain and adds the first samples of its
+track to its parent. Must be called after every bus routed into it.
+*/
+func (b *Bus) process(samples int) {
+	a := b.mixer
+
+	for _, e := range b.effects {
+		e.Process(b.track, samples)
+	}
+
+	out := a.masterTrack
+	if b.parent != nil {
+		out = b.parent.track
+	}
+
+	gains := a.blocks.gain[:samples]
+	b.gain.fill(gains)
+	b.audible.mul(gains)
+	if b.duck != nil {
+		b.duck.mul(gains, a.blocks.level[:samples], a.spec.Channels)
+	}
+
+	for _, c := range a.spec.Channels {
+		// kept for sidechains and meters
+		track := b.track[c][:samples]
+		mulBlock(track, gains)
+		addBlock(out[c][:samples], track)
+		b.meter.add(track)
+	}
+	b.meter.publish(len(a.spec.Channels), a.spec.Frequency)
+
+	if b.duck != nil {
+		b.ducking.Store(math.Float32bits(b.duck.gain))
+	}
+}
+
+// returns the bus a category plays on by default, named "sfx", "music", "ui" and "voice"
+func CategoryBus(c Category) *Bus {
+	return Mixer.categoryBus(c)
+}
+
+// creates a bus routed into parent, or the master track if parent is nil
+func NewBus(name string, parent *Bus) (*Bus, error) {
+	Mixer.mtx.Lock()
+	defer Mixer.mtx.Unlock()
+
+	Mixer.defaultBuses()
+
+	if Mixer.findBus(name) != nil {
+		return nil, debug.Errorf("Bus %q already exists", name)
+	}
+
+	return Mixer.newBus(name, parent), nil
+}
+
+// returns the bus with the given name or nil if there is none
+func FindBus(name string) *Bus {
+	Mixer.mtx.Lock()
+	defer Mixer.mtx.Unlock()
+
+	Mixer.defaultBuses()
+	return Mixer.findBus(name)
+}
+
+func (a *audioMixer) findBus(name string) *Bus {
+	for _, b := range a.buses {
+		if b.name == name {
+			return b
+		}
+	}
+	return nil
+}
+
+func (b *Bus) Name() string {
+	return b.name
+}
+
+// sets the gain applied to everything played on the bus, changes are ramped to avoid clicks
+func (b *Bus) SetGain(gain float32) {
+	b.mixer.commands.push(func() {
+		b.gain.set(max(gain, 0), b.mixer.rampSamples)
+	})
+}
+
+// silences the bus and everything routed into it
+func (b *Bus) SetMute(mute bool) {
+	b.mixer.commands.push(func() {
+		b.mute = mute
+	})
+}
+
+// while any bus is soloed only soloed buses, the buses routed into them and
+// the buses they are routed into can be heard
+func (b *Bus) SetSolo(solo bool) {
+	b.mixer.commands.push(func() {
+		b.solo = solo
+	})
+}
+
+// inserts an effect after the ones already on the bus, it processes the
+// submix before the bus gain is applied
+func (b *Bus) AddEffect(e Effect) {
+	b.mixer.commands.push(func() {
+		e.Init(b.mixer.spec)
+		b.effects = append(b.effects, e)
+	})
+}
+
+func (b *Bus) RemoveEffect(e Effect) {
+	b.mixer.commands.push(func() {
+		b.effects = slices.DeleteFunc(b.effects, func(other Effect) bool { return other == e })
+	})
+}
+
+/*
+Duck lowers the bus to cfg.Gain while the output of sidechain peaks above
+cfg.Threshold, for example to turn the music down under dialogue. It replaces
+any previous ducking of the bus and fails if sidechain depends on the bus.
+*/
+func (b *Bus) Duck(sidechain *Bus, cfg DuckConfig) error {
+	a := b.mixer
+
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	prev := b.sidechain
+	b.sidechain = sidechain
+
+	if err := a.sortBuses(); err != nil {
+		b.sidechain = prev
+		return debug.ErrorWrapf(err, "Failed to duck %q by %q", b.name, sidechain.name)
+	}
+
+	d := &ducker{cfg: cfg, sidechain: sidechain, gain: 1}
+	a.commands.push(func() {
+		if b.duck != nil {
+			d.gain = b.duck.gain
+		}
+		d.configure(a.spec.Frequency)
+		b.duck = d
+	})
+	return nil
+}
+
+// stops ducking the bus, the gain returns to normal right away
+func (b *Bus) StopDucking() {
+	a := b.mixer
+
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	a.commands.push(func() {
+		b.duck = nil
+		b.ducking.Store(math.Float32bits(1))
+	})
+
+	b.sidechain = nil
+	// cannot fail as removing an edge cannot add a cycle
+	_ = a.sortBuses()
+}
+
+// returns the gain the bus was ducked to in the last mixed block, 1 if it is not ducked
+func (b *Bus) Ducking() float32 {
+	return math.Float32frombits(b.ducking.Load())
+}
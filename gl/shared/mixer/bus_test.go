@@ -0,0 +1,128 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mixer
+
+import (
+	"testing"
+
+	"goarrg.com/asset/audio"
+)
+
+func TestBusRouting(t *testing.T) {
+	a := newTestMixer()
+	music := a.categoryBuses[CategoryMusic]
+	sub := a.newBus("sub", music)
+
+	a.play(constantAsset(0.5, 10000), PlayConfig{Bus: sub})
+	a.play(constantAsset(0.25, 10000), PlayConfig{Category: CategoryMusic})
+	a.play(constantAsset(0.125, 10000), PlayConfig{})
+
+	sub.gain.set(0.5, 0)
+	music.gain.set(0.5, 0)
+	a.mix(10)
+
+	assertNear(t, "mix", a.masterTrack[audio.ChannelLeft][0], (0.5*0.5+0.25)*0.5+0.125)
+	assertNear(t, "bus output", music.track[audio.ChannelLeft][0], (0.5*0.5+0.25)*0.5)
+
+	a.categoryBuses[CategorySFX].effects = []Effect{NewDelay(0.001, 0, 1)}
+	a.categoryBuses[CategorySFX].effects[0].Init(a.spec)
+	a.mix(10)
+
+	assertNear(t, "bus effect", a.masterTrack[audio.ChannelLeft][0], (0.5*0.5+0.25)*0.5)
+}
+
+func TestBusMuteSolo(t *testing.T) {
+	a := newTestMixer()
+	music := a.categoryBuses[CategoryMusic]
+	sfx := a.categoryBuses[CategorySFX]
+	sub := a.newBus("sub", music)
+
+	a.play(constantAsset(0.5, 10000), PlayConfig{Bus: sub})
+	a.play(constantAsset(0.25, 10000), PlayConfig{Category: CategoryMusic})
+	a.play(constantAsset(0.125, 10000), PlayConfig{})
+
+	tests := []struct {
+		name  string
+		setup func()
+		want  float32
+	}{
+		{"mute", func() { sfx.mute = true }, 0.75},
+		{"solo child", func() { sfx.mute = false; sub.solo = true }, 0.75},
+		{"solo parent", func() { sub.solo = false; music.solo = true }, 0.75},
+		{"mute child", func() { sub.mute = true }, 0.25},
+		{"solo two", func() { sub.mute = false; sfx.solo = true }, 0.875},
+		{"unsolo", func() { sfx.solo = false; music.solo = false }, 0.875},
+	}
+
+	for _, test := range tests {
+		test.setup()
+
+		// mute and solo are ramped
+		a.mix(a.rampSamples + 10)
+		assertNear(t, test.name, a.masterTrack[audio.ChannelLeft][a.rampSamples], test.want)
+	}
+}
+
+func TestBusDucking(t *testing.T) {
+	a := newTestMixer()
+	music := a.categoryBuses[CategoryMusic]
+	voice := a.categoryBuses[CategoryVoice]
+
+	err := music.Duck(voice, DuckConfig{Gain: 0.25, Threshold: 0.01, Attack: 0.01, Release: 0.1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a.play(constantAsset(0.5, testFrequency*2), PlayConfig{Category: CategoryMusic})
+	a.play(constantAsset(0.1, testFrequency/2), PlayConfig{Category: CategoryVoice})
+
+	// attack and release are one pole filters with these coefficients
+	attack, release := onePole(0.01, testFrequency), onePole(0.1, testFrequency)
+	gain := float32(1)
+
+	for i, s := range render(a, testFrequency) {
+		dialogue := float32(0.1)
+		if i >= testFrequency/2 {
+			dialogue, gain = 0, gain+(1-gain)*release
+		} else {
+			gain += (0.25 - gain) * attack
+		}
+
+		if abs(s-(0.5*gain+dialogue)) > 1e-4 {
+			t.Fatalf("Sample %d = %f, want %f", i, s, 0.5*gain+dialogue)
+		}
+	}
+
+	if d := music.Ducking(); d < 0.99 {
+		t.Fatalf("Ducking released to %f", d)
+	}
+
+	if err := voice.Duck(music, DuckConfig{}); err == nil {
+		t.Fatal("Ducking each other did not fail")
+	}
+	if voice.sidechain != nil {
+		t.Fatal("Failed duck was kept")
+	}
+
+	sfx := a.categoryBuses[CategorySFX]
+	if err := a.newBus("child", sfx).Duck(sfx, DuckConfig{}); err == nil {
+		t.Fatal("Ducking by the parent did not fail")
+	}
+}
@@ -0,0 +1,139 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mixer
+
+import (
+	"math"
+	"slices"
+
+	"goarrg.com/asset/audio"
+)
+
+// -3dB, the ITU-R BS.775 downmix coefficient
+const minus3dB = math.Sqrt2 / 2
+
+// channelRoute adds one source channel into one output channel
+type channelRoute struct {
+	// index into the source's channels
+	in   int
+	out  audio.Channel
+	gain float32
+}
+
+type channelFold struct {
+	out  audio.Channel
+	gain float32
+}
+
+/*
+folds for source channels missing from the output, the first group whose
+channels all exist in the output is used, channels without a usable group
+are dropped, which is what happens to the LFE channel when downmixing.
+*/
+var channelFolds = map[audio.Channel][][]channelFold{
+	audio.ChannelLeft: {
+		{{audio.ChannelCenter, 1}},
+	},
+	audio.ChannelRight: {
+		{{audio.ChannelCenter, 1}},
+	},
+	audio.ChannelCenter: {
+		{{audio.ChannelLeft, minus3dB}, {audio.ChannelRight, minus3dB}},
+	},
+	audio.ChannelSurroundLeft: {
+		{{audio.ChannelBackSurroundLeft, 1}},
+		{{audio.ChannelLeft, minus3dB}},
+	},
+	audio.ChannelSurroundRight: {
+		{{audio.ChannelBackSurroundRight, 1}},
+		{{audio.ChannelRight, minus3dB}},
+	},
+	audio.ChannelBackSurroundLeft: {
+		{{audio.ChannelSurroundLeft, 1}},
+		{{audio.ChannelLeft, minus3dB}},
+	},
+	audio.ChannelBackSurroundRight: {
+		{{audio.ChannelSurroundRight, 1}},
+		{{audio.ChannelRight, minus3dB}},
+	},
+}
+
+// gains used when everything is folded into a single output channel
+var monoFolds = map[audio.Channel]float32{
+	audio.ChannelLeft:              minus3dB,
+	audio.ChannelRight:             minus3dB,
+	audio.ChannelCenter:            1,
+	audio.ChannelSurroundLeft:      0.5,
+	audio.ChannelSurroundRight:     0.5,
+	audio.ChannelBackSurroundLeft:  0.5,
+	audio.ChannelBackSurroundRight: 0.5,
+}
+
+/*
+channelRoutes maps every channel of a source with the in layout onto the out
+layout. Channels present in both are copied, a single channel source is
+treated as mono and sent to both front channels so it can still be panned,
+and everything else is folded down with the ITU-R BS.775 coefficients.
+*/
+func channelRoutes(in, out []audio.Channel) []channelRoute {
+	routes := []channelRoute{}
+	has := func(c audio.Channel) bool { return slices.Contains(out, c) }
+
+	if len(in) == 1 {
+		switch {
+		case has(audio.ChannelLeft) && has(audio.ChannelRight):
+			return append(routes,
+				channelRoute{0, audio.ChannelLeft, 1},
+				channelRoute{0, audio.ChannelRight, 1},
+			)
+		case len(out) > 0:
+			return append(routes, channelRoute{0, out[0], 1})
+		}
+		return routes
+	}
+
+	for src, c := range in {
+		switch {
+		case len(out) == 1:
+			if gain, ok := monoFolds[c]; ok {
+				routes = append(routes, channelRoute{src, out[0], gain})
+			}
+
+		case has(c):
+			routes = append(routes, channelRoute{src, c, 1})
+
+		default:
+		groups:
+			for _, group := range channelFolds[c] {
+				for _, f := range group {
+					if !has(f.out) {
+						continue groups
+					}
+				}
+				for _, f := range group {
+					routes = append(routes, channelRoute{src, f.out, f.gain})
+				}
+				break
+			}
+		}
+	}
+
+	return routes
+}
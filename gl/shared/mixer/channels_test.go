@@ -0,0 +1,95 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mixer
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"goarrg.com/asset/audio"
+)
+
+// returns an asset where channel i is the constant i+1 so every channel can
+// be told apart in the mix
+func layoutAsset(channels []audio.Channel) audio.Asset {
+	spec := audio.Spec{Channels: channels, Frequency: testFrequency}
+	track := make(audio.Track)
+
+	for i, c := range channels {
+		track[c] = make([]float32, 100)
+		for j := range track[c] {
+			track[c][j] = float32(i+1) / 64
+		}
+	}
+
+	return newTrackAsset(spec, track)
+}
+
+func TestChannelMapping(t *testing.T) {
+	const h = math.Sqrt2 / 2
+
+	// values are in units of 1/64, see layoutAsset
+	tests := []struct {
+		name    string
+		in, out []audio.Channel
+		want    map[audio.Channel]float32
+	}{
+		{"mono to stereo", audio.ChannelsMono(), audio.ChannelsStereo(), map[audio.Channel]float32{
+			audio.ChannelLeft: 1, audio.ChannelRight: 1,
+		}},
+		{"stereo to mono", audio.ChannelsStereo(), audio.ChannelsMono(), map[audio.Channel]float32{
+			audio.ChannelLeft: 1*h + 2*h,
+		}},
+		{"5.1 to stereo", audio.Channels5Point1(), audio.ChannelsStereo(), map[audio.Channel]float32{
+			audio.ChannelLeft: 1 + 3*h + 5*h, audio.ChannelRight: 2 + 3*h + 6*h,
+		}},
+		{"7.1 to stereo", audio.Channels7Point1(), audio.ChannelsStereo(), map[audio.Channel]float32{
+			audio.ChannelLeft: 1 + 3*h + 5*h + 7*h, audio.ChannelRight: 2 + 3*h + 6*h + 8*h,
+		}},
+		{"7.1 to 5.1", audio.Channels7Point1(), audio.Channels5Point1(), map[audio.Channel]float32{
+			audio.ChannelLeft: 1, audio.ChannelRight: 2, audio.ChannelCenter: 3, audio.ChannelLowFrequency: 4,
+			audio.ChannelSurroundLeft: 5 + 7, audio.ChannelSurroundRight: 6 + 8,
+		}},
+		{"stereo to 5.1", audio.ChannelsStereo(), audio.Channels5Point1(), map[audio.Channel]float32{
+			audio.ChannelLeft: 1, audio.ChannelRight: 2,
+		}},
+	}
+
+	for _, test := range tests {
+		a := &audioMixer{limiterConfig: LimiterConfig{Disabled: true}}
+		a.init(audio.Spec{Channels: test.out, Frequency: testFrequency})
+		a.play(layoutAsset(test.in), PlayConfig{})
+		a.mix(1)
+
+		for _, c := range test.out {
+			assertNear(t, fmt.Sprintf("%s %v", test.name, c), a.masterTrack[c][0]*64, test.want[c])
+		}
+	}
+}
+
+func TestChannelMappingMonoPan(t *testing.T) {
+	a := newTestMixer()
+	a.play(layoutAsset(audio.ChannelsMono()), PlayConfig{Pan: -1})
+	a.mix(1)
+
+	assertNear(t, "left", a.masterTrack[audio.ChannelLeft][0]*64, math.Sqrt2)
+	assertNear(t, "right", a.masterTrack[audio.ChannelRight][0]*64, 0)
+}
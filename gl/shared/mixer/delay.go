@@ -0,0 +1,128 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mixer
+
+import (
+	"math"
+
+	"goarrg.com/asset/audio"
+)
+
+// the delay line is allocated for at least this many seconds so the time can be automated
+const minDelayLineSeconds = 2
+
+/*
+Delay is an echo with feedback, the delay time is read with linear
+interpolation so it can be swept without steps.
+*/
+type Delay struct {
+	maxSeconds float32
+	time       param
+	feedback   param
+	mix        param
+	frequency  float32
+	channels   []audio.Channel
+
+	lines [][]float32
+	pos   int
+	// the slices of the track being processed, looked up once per block
+	inputs [][]float32
+}
+
+/*
+NewDelay returns an echo repeating after the given seconds, each repeat is
+scaled by feedback and mix blends between the dry and delayed signal.
+*/
+func NewDelay(seconds, feedback, mix float32) *Delay {
+	d := &Delay{maxSeconds: max(seconds, minDelayLineSeconds)}
+	d.time.init(seconds)
+	d.feedback.init(feedback)
+	d.mix.init(mix)
+	return d
+}
+
+// seconds between repeats, clamped to the delay line allocated by NewDelay
+func (d *Delay) SetTime(seconds float32) {
+	d.time.set(seconds)
+}
+
+func (d *Delay) SetFeedback(feedback float32) {
+	d.feedback.set(feedback)
+}
+
+func (d *Delay) SetMix(mix float32) {
+	d.mix.set(mix)
+}
+
+func (d *Delay) Init(spec audio.Spec) {
+	d.frequency = float32(spec.Frequency)
+	d.channels = spec.Channels
+	d.pos = 0
+
+	size := int(math.Ceil(float64(d.maxSeconds*d.frequency))) + 2
+	d.lines = make([][]float32, len(spec.Channels))
+	d.inputs = make([][]float32, len(spec.Channels))
+	for c := range d.lines {
+		d.lines[c] = make([]float32, size)
+	}
+
+	samples := automationSamples(spec.Frequency)
+	for _, p := range []*param{&d.time, &d.feedback, &d.mix} {
+		p.samples = samples
+		p.ramp = newRamp(p.target())
+	}
+}
+
+func (d *Delay) Process(track audio.Track, samples int) {
+	d.time.update()
+	d.feedback.update()
+	d.mix.update()
+
+	size := len(d.lines[0])
+	for c, ch := range d.channels {
+		d.inputs[c] = track[ch][:samples]
+	}
+
+	for i := 0; i < samples; i++ {
+		delay := min(max(d.time.next()*d.frequency, 1), float32(size-2))
+		feedback := d.feedback.next()
+		mix := d.mix.next()
+
+		// fractional read position behind the write position
+		read := float32(d.pos) - delay
+		if read < 0 {
+			read += float32(size)
+		}
+		r0 := int(read)
+		r1 := (r0 + 1) % size
+		frac := read - float32(r0)
+
+		for c, buf := range d.inputs {
+			line := d.lines[c]
+			delayed := line[r0] + (line[r1]-line[r0])*frac
+			in := buf[i]
+
+			line[d.pos] = in + delayed*feedback
+			buf[i] = in*(1-mix) + delayed*mix
+		}
+
+		d.pos = (d.pos + 1) % size
+	}
+}
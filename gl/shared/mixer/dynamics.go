@@ -0,0 +1,392 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mixer
+
+import (
+	"math"
+	"sync/atomic"
+
+	"goarrg.com/asset/audio"
+)
+
+const (
+	defaultLimiterLookahead = 0.005
+	defaultLimiterRelease   = 0.05
+)
+
+type LimiterConfig struct {
+	// peak level the output never exceeds in linear gain, 0 is treated as 1
+	Ceiling float32
+	// seconds the output is delayed so gain reduction starts before a peak,
+	// 0 is treated as 5ms
+	Lookahead float64
+	// seconds to recover from gain reduction, 0 is treated as 50ms
+	Release float64
+	// turns the limiter off, the output is then clamped to [-1, 1]
+	Disabled bool
+}
+
+type CompressorConfig struct {
+	// level in dBFS above which the signal is compressed
+	Threshold float32
+	// input dB above the threshold per output dB, 1 or less disables the compressor
+	Ratio   float32
+	Attack  float64
+	Release float64
+	// gain in dB applied after compression
+	Makeup float32
+}
+
+// DynamicsMeter reports gain reduction in positive dB, 0 means the signal passed unchanged
+type DynamicsMeter struct {
+	Compressor float32
+	Limiter    float32
+	// largest reduction since the last call to Dynamics
+	CompressorPeak float32
+	LimiterPeak    float32
+}
+
+/*
+limiter is a stereo linked look-ahead peak limiter. The gain needed by every
+sample is min filtered over the look-ahead window and then averaged over it,
+with the output delayed by the window every sample is reduced enough by the
+time it leaves the delay line while the gain changes linearly instead of
+jumping.
+*/
+type limiter struct {
+	disabled bool
+	ceiling  float32
+	window   int
+	release  float32
+
+	// delay line per output channel
+	delay [][]float32
+	pos   int
+	// sliding minimum of the needed gain, a monotonic queue of sample indices
+	minIndex []int64
+	minValue []float32
+	minHead  int
+	minLen   int
+	// moving average of the minimum over the window
+	avg    []float32
+	avgSum float64
+	n      int64
+	gain   float32
+
+	reduction float32
+	peak      float32
+
+	// scratch blocks of the linked peaks and gains
+	levels []float32
+	gains  []float32
+}
+
+func (l *limiter) configure(cfg LimiterConfig, frequency, channels int) {
+	if cfg.Ceiling <= 0 {
+		cfg.Ceiling = 1
+	}
+	if cfg.Lookahead <= 0 {
+		cfg.Lookahead = defaultLimiterLookahead
+	}
+	if cfg.Release <= 0 {
+		cfg.Release = defaultLimiterRelease
+	}
+
+	*l = limiter{
+		disabled: cfg.Disabled,
+		ceiling:  cfg.Ceiling,
+		window:   max(int(cfg.Lookahead*float64(frequency)), 1),
+		release:  onePole(cfg.Release, frequency),
+		gain:     1,
+	}
+
+	l.delay = make([][]float32, channels)
+	for c := range l.delay {
+		l.delay[c] = make([]float32, l.window)
+	}
+	l.minIndex = make([]int64, l.window+2)
+	l.minValue = make([]float32, l.window+2)
+	l.avg = make([]float32, l.window)
+	for i := range l.avg {
+		l.avg[i] = 1
+	}
+	l.avgSum = float64(l.window)
+
+	l.levels = make([]float32, int(maxLatencySeconds*float64(frequency)))
+	l.gains = make([]float32, len(l.levels))
+}
+
+// limits the first samples of every channel in place
+func (l *limiter) process(track audio.Track, channels []audio.Channel, samples int) {
+	if l.disabled {
+		for _, c := range channels {
+			for i, s := range track[c][:samples] {
+				track[c][i] = min(max(s, -1), 1)
+			}
+		}
+		return
+	}
+
+	levels := scratchBlock(&l.levels, samples)
+	gains := scratchBlock(&l.gains, samples)
+	linkedPeaks(levels, track, channels)
+
+	lowest := float32(1)
+	for i, peak := range levels {
+		needed := float32(1)
+		if peak > l.ceiling {
+			needed = l.ceiling / peak
+		}
+
+		target := l.pushAverage(l.pushMin(needed))
+
+		if target < l.gain {
+			l.gain = target
+		} else {
+			l.gain += (target - l.gain) * l.release
+		}
+
+		gains[i] = l.gain
+		lowest = min(lowest, l.gain)
+	}
+
+	for ch, c := range channels {
+		delay := l.delay[ch]
+		pos := l.pos
+
+		for i, s := range track[c][:samples] {
+			delayed := delay[pos]
+			delay[pos] = s
+			// rounding can leave the gain a hair above what is needed
+			track[c][i] = min(max(delayed*gains[i], -l.ceiling), l.ceiling)
+
+			if pos++; pos == l.window {
+				pos = 0
+			}
+		}
+	}
+	l.pos = (l.pos + samples) % l.window
+
+	l.reduction = gainToDB(l.gain)
+	l.peak = max(l.peak, gainToDB(lowest))
+}
+
+// adds the gain needed by the newest sample and returns the minimum over the
+// newest window+1 samples
+func (l *limiter) pushMin(needed float32) float32 {
+	size := len(l.minIndex)
+
+	for l.minLen > 0 {
+		last := (l.minHead + l.minLen - 1) % size
+		if l.minValue[last] < needed {
+			break
+		}
+		l.minLen--
+	}
+	for l.minLen > 0 && l.n-l.minIndex[l.minHead] > int64(l.window) {
+		l.minHead = (l.minHead + 1) % size
+		l.minLen--
+	}
+
+	tail := (l.minHead + l.minLen) % size
+	l.minIndex[tail] = l.n
+	l.minValue[tail] = needed
+	l.minLen++
+	l.n++
+
+	return l.minValue[l.minHead]
+}
+
+// returns the average of the last window values pushed
+func (l *limiter) pushAverage(v float32) float32 {
+	i := int(l.n % int64(l.window))
+	l.avgSum += float64(v) - float64(l.avg[i])
+	l.avg[i] = v
+
+	return float32(l.avgSum / float64(l.window))
+}
+
+/*
+compressor is a feed forward compressor with a peak detector linked across
+channels, attack and release smooth the gain reduction in dB.
+*/
+type compressor struct {
+	enabled   bool
+	threshold float32
+	slope     float32
+	attack    float32
+	release   float32
+	makeup    float32
+
+	reduction float32
+	peak      float32
+
+	// scratch blocks of the linked peaks and gains
+	levels []float32
+	gains  []float32
+}
+
+func (c *compressor) configure(cfg CompressorConfig, frequency int) {
+	*c = compressor{
+		enabled:   cfg.Ratio > 1,
+		threshold: cfg.Threshold,
+		slope:     1 - 1/max(cfg.Ratio, 1),
+		attack:    onePole(cfg.Attack, frequency),
+		release:   onePole(cfg.Release, frequency),
+		makeup:    dbToGain(cfg.Makeup),
+		levels:    make([]float32, int(maxLatencySeconds*float64(frequency))),
+	}
+	c.gains = make([]float32, len(c.levels))
+}
+
+func (c *compressor) process(track audio.Track, channels []audio.Channel, samples int) {
+	if !c.enabled {
+		return
+	}
+
+	levels := scratchBlock(&c.levels, samples)
+	gains := scratchBlock(&c.gains, samples)
+	linkedPeaks(levels, track, channels)
+
+	for i, peak := range levels {
+		target := max((-gainToDB(peak)-c.threshold)*c.slope, 0)
+		if target > c.reduction {
+			c.reduction += (target - c.reduction) * c.attack
+		} else {
+			c.reduction += (target - c.reduction) * c.release
+		}
+		c.peak = max(c.peak, c.reduction)
+
+		gains[i] = dbToGain(-c.reduction) * c.makeup
+	}
+
+	for _, ch := range channels {
+		mulBlock(track[ch][:samples], gains)
+	}
+}
+
+// returns the per sample coefficient of a one pole filter reaching ~63% of a
+// step after the given seconds, 0 seconds follows instantly
+func onePole(seconds float64, frequency int) float32 {
+	if seconds <= 0 {
+		return 1
+	}
+	return float32(1 - math.Exp(-1/(seconds*float64(frequency))))
+}
+
+// returns the attenuation of gain in positive dB, silence is capped at 200dB
+func gainToDB(gain float32) float32 {
+	return float32(-20 * math.Log10(max(float64(gain), 1e-10)))
+}
+
+func dbToGain(db float32) float32 {
+	return float32(math.Pow(10, float64(db)/20))
+}
+
+// configures the limiter on the master track, it replaces clamping the output
+func SetLimiter(cfg LimiterConfig) {
+	Mixer.setLimiter(cfg)
+}
+
+func (a *audioMixer) setLimiter(cfg LimiterConfig) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	a.limiterConfig = cfg
+
+	// before Init there is nothing to reconfigure, init uses the config
+	if a.spec.Frequency == 0 {
+		return
+	}
+
+	var l limiter
+	l.configure(cfg, a.spec.Frequency, len(a.spec.Channels))
+	a.commands.push(func() { a.limiter = l })
+}
+
+// configures the compressor applied to the master track before the limiter
+func SetCompressor(cfg CompressorConfig) {
+	Mixer.setCompressor(cfg)
+}
+
+func (a *audioMixer) setCompressor(cfg CompressorConfig) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	a.compressorConfig = cfg
+
+	if a.spec.Frequency == 0 {
+		return
+	}
+
+	var c compressor
+	c.configure(cfg, a.spec.Frequency)
+	a.commands.push(func() { a.compressor = c })
+}
+
+// returns the current gain reduction of the master track and resets the peaks
+func Dynamics() DynamicsMeter {
+	return Mixer.dynamics()
+}
+
+// gain reductions as float32 bits, the peaks are reset by the game thread
+type dynamicsMeter struct {
+	compressor     atomic.Uint32
+	limiter        atomic.Uint32
+	compressorPeak atomic.Uint32
+	limiterPeak    atomic.Uint32
+}
+
+// publishes the gain reduction of the last block, only called by the audio thread
+func (a *audioMixer) publishDynamics() {
+	m := &a.published.dynamics
+
+	m.compressor.Store(math.Float32bits(a.compressor.reduction))
+	m.limiter.Store(math.Float32bits(a.limiter.reduction))
+	storeMax(&m.compressorPeak, a.compressor.peak)
+	storeMax(&m.limiterPeak, a.limiter.peak)
+
+	a.compressor.peak = a.compressor.reduction
+	a.limiter.peak = a.limiter.reduction
+}
+
+// raises the float32 stored in bits to f unless it is already larger
+func storeMax(bits *atomic.Uint32, f float32) {
+	for {
+		old := bits.Load()
+		if math.Float32frombits(old) >= f || bits.CompareAndSwap(old, math.Float32bits(f)) {
+			return
+		}
+	}
+}
+
+func (a *audioMixer) dynamics() DynamicsMeter {
+	m := &a.published.dynamics
+
+	compressor := m.compressor.Load()
+	limiter := m.limiter.Load()
+
+	return DynamicsMeter{
+		Compressor:     math.Float32frombits(compressor),
+		Limiter:        math.Float32frombits(limiter),
+		CompressorPeak: math.Float32frombits(m.compressorPeak.Swap(compressor)),
+		LimiterPeak:    math.Float32frombits(m.limiterPeak.Swap(limiter)),
+	}
+}
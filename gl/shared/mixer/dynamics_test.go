@@ -0,0 +1,219 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mixer
+
+import (
+	"math"
+	"testing"
+
+	"goarrg.com/asset/audio"
+)
+
+// returns a stereo track of the signal on both channels
+func signalTrack(signal func(i int) float32, samples int) audio.Track {
+	track := audio.Track{}
+	for _, c := range audio.ChannelsStereo() {
+		track[c] = make([]float32, samples)
+		for i := range track[c] {
+			track[c][i] = signal(i)
+		}
+	}
+	return track
+}
+
+func sine(amplitude float32, hz float64) func(int) float32 {
+	return func(i int) float32 {
+		return amplitude * float32(math.Sin(2*math.Pi*hz*float64(i)/testFrequency))
+	}
+}
+
+func TestLimiterCeiling(t *testing.T) {
+	var l limiter
+	l.configure(LimiterConfig{Ceiling: 0.5}, testFrequency, 2)
+
+	// quiet, then bursts far above the ceiling
+	signal := func(i int) float32 {
+		if (i/2000)%2 == 1 {
+			return sine(4, 997)(i)
+		}
+		return sine(0.1, 441)(i)
+	}
+
+	const samples = 20000
+	track := signalTrack(signal, samples)
+	in := track[audio.ChannelLeft][:samples:samples]
+	in = append([]float32(nil), in...)
+
+	// odd block sizes to cover state kept across calls
+	for start := 0; start < samples; start += 333 {
+		n := min(333, samples-start)
+		block := audio.Track{}
+		for c, s := range track {
+			block[c] = s[start : start+n]
+		}
+		l.process(block, audio.ChannelsStereo(), n)
+	}
+
+	out := track[audio.ChannelLeft]
+	for i, s := range out {
+		if abs(s) > 0.5+1e-6 {
+			t.Fatalf("Sample %d = %f exceeds the ceiling", i, s)
+		}
+	}
+
+	// the quiet part before the first burst comes out unchanged after the look-ahead
+	for i := l.window; i < 2000-l.window; i++ {
+		assertNear(t, "quiet sample", out[i], in[i-l.window])
+	}
+}
+
+func TestLimiterSmoothGain(t *testing.T) {
+	var l limiter
+	l.configure(LimiterConfig{}, testFrequency, 2)
+
+	// a step from 0.5 to 2, the gain must ramp down before the step leaves the
+	// delay line instead of jumping
+	const samples = 4000
+	track := signalTrack(func(i int) float32 {
+		if i < 1000 {
+			return 0.5
+		}
+		return 2
+	}, samples)
+	l.process(track, audio.ChannelsStereo(), samples)
+
+	out := track[audio.ChannelLeft]
+	in := func(i int) float32 {
+		if i < 1000 {
+			return 0.5
+		}
+		return 2
+	}
+	maxStep := float32(0.5/float64(l.window)) + 1e-5
+
+	for i := l.window + 1; i < samples; i++ {
+		before := out[i-1] / in(i-1-l.window)
+		after := out[i] / in(i-l.window)
+		if d := abs(after - before); d > maxStep {
+			t.Fatalf("Gain jumped by %f at %d", d, i)
+		}
+	}
+
+	assertNear(t, "limited step", out[samples-1], 1)
+	assertNear(t, "gain reduction", l.reduction, float32(20*math.Log10(2)))
+}
+
+func TestLimiterRelease(t *testing.T) {
+	var l limiter
+	l.configure(LimiterConfig{Release: 0.01}, testFrequency, 2)
+
+	const samples = 10000
+	track := signalTrack(func(i int) float32 {
+		if i < 1000 {
+			return 4
+		}
+		return 0.25
+	}, samples)
+	l.process(track, audio.ChannelsStereo(), samples)
+
+	if l.peak < 12 {
+		t.Fatalf("Peak reduction %fdB, want 12dB", l.peak)
+	}
+	if l.reduction > 0.01 {
+		t.Fatalf("Still reducing %fdB after the release", l.reduction)
+	}
+	assertNear(t, "released sample", track[audio.ChannelLeft][samples-1], 0.25)
+}
+
+func TestCompressor(t *testing.T) {
+	var c compressor
+	c.configure(CompressorConfig{Threshold: -20, Ratio: 4, Makeup: 6}, testFrequency)
+
+	track := signalTrack(func(int) float32 { return 1 }, 100)
+	c.process(track, audio.ChannelsStereo(), 100)
+
+	// 20dB over the threshold at 4:1 leaves 5dB over it
+	assertNear(t, "reduction", c.reduction, 15)
+	assertNear(t, "output", track[audio.ChannelLeft][99], dbToGain(-15+6))
+
+	quiet := signalTrack(func(int) float32 { return 0.05 }, 100)
+	c.configure(CompressorConfig{Threshold: -20, Ratio: 4}, testFrequency)
+	c.process(quiet, audio.ChannelsStereo(), 100)
+	assertNear(t, "below threshold", quiet[audio.ChannelLeft][99], 0.05)
+}
+
+func TestCompressorAttack(t *testing.T) {
+	var c compressor
+	c.configure(CompressorConfig{Threshold: -20, Ratio: 4, Attack: 0.01, Release: 0.1}, testFrequency)
+
+	samples := testFrequency / 100
+	track := signalTrack(func(int) float32 { return 1 }, samples)
+	c.process(track, audio.ChannelsStereo(), samples)
+
+	// one time constant reaches ~63% of the reduction
+	if c.reduction < 15*0.6 || c.reduction > 15*0.66 {
+		t.Fatalf("Reduction %fdB after the attack time, want ~%fdB", c.reduction, 15*0.63)
+	}
+}
+
+func TestDynamicsBeforeInit(t *testing.T) {
+	a := &audioMixer{}
+	a.setLimiter(LimiterConfig{Ceiling: 0.5})
+	a.setCompressor(CompressorConfig{Threshold: -20, Ratio: 4, Attack: 0.01, Release: 0.1})
+	a.init(audio.Spec{Channels: audio.ChannelsStereo(), Frequency: testFrequency})
+
+	a.play(constantAsset(0.75, 1000), PlayConfig{})
+	a.mix(1000)
+
+	if a.limiter.ceiling != 0.5 || len(a.limiter.delay) != 2 {
+		t.Fatalf("Limiter not configured by init: %+v", a.limiter)
+	}
+	if a.compressor.attack != onePole(0.01, testFrequency) {
+		t.Fatalf("Compressor attack %f", a.compressor.attack)
+	}
+	for i, s := range a.masterTrack[audio.ChannelLeft][:1000] {
+		if s > 0.5 {
+			t.Fatalf("Sample %d = %f exceeds the ceiling", i, s)
+		}
+	}
+}
+
+func TestMixLimiter(t *testing.T) {
+	a := &audioMixer{}
+	a.init(audio.Spec{Channels: audio.ChannelsStereo(), Frequency: testFrequency})
+
+	for range 4 {
+		a.play(constantAsset(0.75, 1000), PlayConfig{})
+	}
+	a.mix(1000)
+
+	for i, s := range a.masterTrack[audio.ChannelLeft][:1000] {
+		if s > 1 {
+			t.Fatalf("Sample %d = %f exceeds full scale", i, s)
+		}
+	}
+
+	if m := a.dynamics(); m.LimiterPeak < 9 || m.Compressor != 0 {
+		t.Fatalf("Meter %+v", m)
+	}
+	if m := a.dynamics(); m.LimiterPeak != m.Limiter {
+		t.Fatalf("Peak not reset %+v", m)
+	}
+}
@@ -0,0 +1,131 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mixer
+
+import (
+	"math"
+	"slices"
+	"sync/atomic"
+
+	"goarrg.com/asset/audio"
+)
+
+// parameter changes are smoothed over this many seconds so automation does not click
+const automationSeconds = 0.02
+
+/*
+Effect processes audio in blocks on the audio thread. An effect instance must
+only be inserted in one place as it keeps state between blocks.
+*/
+type Effect interface {
+	// prepares the effect for the channels and frequency it will process,
+	// called before the first Process, on the audio thread once mixing started
+	Init(spec audio.Spec)
+	// processes the first samples of every channel of track in place
+	Process(track audio.Track, samples int)
+}
+
+/*
+param is an effect parameter that can be set from any goroutine, the audio
+thread picks up the new value at the start of a block and ramps to it.
+*/
+type param struct {
+	bits    atomic.Uint32
+	ramp    ramp
+	samples int
+}
+
+func (p *param) init(value float32) {
+	p.bits.Store(math.Float32bits(value))
+	p.ramp = newRamp(value)
+}
+
+func (p *param) set(value float32) {
+	p.bits.Store(math.Float32bits(value))
+}
+
+func (p *param) target() float32 {
+	return math.Float32frombits(p.bits.Load())
+}
+
+// starts ramping to the last value set, called at the start of every block
+func (p *param) update() {
+	if t := p.target(); t != p.ramp.target {
+		p.ramp.set(t, p.samples)
+	}
+}
+
+func (p *param) next() float32 {
+	return p.ramp.next()
+}
+
+func (p *param) ramping() bool {
+	return p.ramp.remaining > 0
+}
+
+// inserts an effect after the ones already on the voice, it processes the
+// sound before gain and pan are applied
+func (v *Voice) AddEffect(e Effect) {
+	v.mixer.commands.push(func() {
+		e.Init(audio.Spec{Channels: v.src.channels(), Frequency: v.mixer.spec.Frequency})
+		v.effects = append(v.effects, e)
+	})
+}
+
+func (v *Voice) RemoveEffect(e Effect) {
+	v.mixer.commands.push(func() {
+		v.effects = slices.DeleteFunc(v.effects, func(other Effect) bool { return other == e })
+	})
+}
+
+// runs the voice's effects on the frames just read from its source
+func (v *Voice) processEffects(block [][]float32, samples int) {
+	if v.effectTrack == nil {
+		v.effectTrack = make(audio.Track, len(block))
+	}
+
+	for i, c := range v.src.channels() {
+		v.effectTrack[c] = block[i]
+	}
+
+	for _, e := range v.effects {
+		e.Process(v.effectTrack, samples)
+	}
+}
+
+// inserts an effect on the master track, it runs after the master gain and
+// before the compressor and limiter
+func AddMasterEffect(e Effect) {
+	Mixer.commands.push(func() {
+		e.Init(Mixer.spec)
+		Mixer.masterEffects = append(Mixer.masterEffects, e)
+	})
+}
+
+func RemoveMasterEffect(e Effect) {
+	Mixer.commands.push(func() {
+		Mixer.masterEffects = slices.DeleteFunc(Mixer.masterEffects, func(other Effect) bool { return other == e })
+	})
+}
+
+// returns how many samples a parameter ramp takes at the given frequency
+func automationSamples(frequency int) int {
+	return int(automationSeconds * float64(frequency))
+}
@@ -0,0 +1,201 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mixer
+
+import (
+	"math"
+	"testing"
+
+	"goarrg.com/asset/audio"
+)
+
+var testSpec = audio.Spec{Channels: audio.ChannelsStereo(), Frequency: testFrequency}
+
+// runs the signal through the effect in blocks and returns the left channel
+func process(e Effect, signal func(i int) float32, samples int) []float32 {
+	e.Init(testSpec)
+	track := signalTrack(signal, samples)
+
+	for start := 0; start < samples; start += 100 {
+		n := min(100, samples-start)
+		block := audio.Track{}
+		for c, s := range track {
+			block[c] = s[start : start+n]
+		}
+		e.Process(block, n)
+	}
+
+	return track[audio.ChannelLeft]
+}
+
+func impulse(i int) float32 {
+	if i == 0 {
+		return 1
+	}
+	return 0
+}
+
+func energy(samples []float32) float64 {
+	sum := 0.0
+	for _, s := range samples {
+		sum += float64(s) * float64(s)
+	}
+	return sum
+}
+
+func TestBiquadImpulse(t *testing.T) {
+	// cutoff at a quarter of the sample rate makes cos(w0) 0 so the
+	// coefficients are easy to derive by hand, alpha is 1/sqrt(2)
+	const a2 = 0.171573
+
+	tests := []struct {
+		kind FilterKind
+		want []float32
+		// sum of the impulse response, the gain at DC
+		dc float32
+	}{
+		{LowPass, []float32{0.292893, 0.585786, 0.292893 - a2*0.292893, -a2 * 0.585786}, 1},
+		{HighPass, []float32{0.292893, -0.585786, 0.292893 - a2*0.292893, a2 * 0.585786}, 0},
+		{BandPass, []float32{0.414214, 0, -0.414214 - a2*0.414214, 0}, 0},
+	}
+
+	for _, test := range tests {
+		out := process(NewBiquad(test.kind, testFrequency/4, math.Sqrt2/2), impulse, 2000)
+
+		for i, want := range test.want {
+			assertNear(t, "impulse response", out[i], want)
+		}
+
+		sum := float32(0)
+		for _, s := range out {
+			sum += s
+		}
+		assertNear(t, "dc gain", sum, test.dc)
+	}
+}
+
+func TestBiquadBandPassPeak(t *testing.T) {
+	out := process(NewBiquad(BandPass, 1000, 2), sine(1, 1000), testFrequency)
+
+	// steady state amplitude at the center frequency is unity
+	peak := float32(0)
+	for _, s := range out[testFrequency/2:] {
+		peak = max(peak, abs(s))
+	}
+	if math.Abs(float64(peak-1)) > 0.01 {
+		t.Fatalf("Peak gain %f at the center frequency, want 1", peak)
+	}
+}
+
+func TestDelayImpulse(t *testing.T) {
+	// 441 samples
+	out := process(NewDelay(0.01, 0.5, 0.5), impulse, 2000)
+
+	want := map[int]float32{0: 0.5, 441: 0.5, 882: 0.25, 1323: 0.125, 1764: 0.0625}
+	for i, s := range out {
+		assertNear(t, "impulse response", s, want[i])
+	}
+}
+
+func TestReverbImpulse(t *testing.T) {
+	cfg := ReverbConfig{RoomSize: 0.5, Damping: 0.5, Wet: 1, Width: 1}
+	out := process(NewReverb(cfg), impulse, testFrequency*2)
+
+	// nothing comes out before the shortest comb
+	for i, s := range out[:reverbCombTuning[0]] {
+		if s != 0 {
+			t.Fatalf("Sample %d = %f before the first reflection", i, s)
+		}
+	}
+
+	half := testFrequency / 2
+	early, late := energy(out[:half]), energy(out[half:2*half])
+	if early == 0 || late >= early || math.IsNaN(early) {
+		t.Fatalf("Energy %f then %f, want a decaying tail", early, late)
+	}
+
+	cfg.RoomSize = 0.9
+	large := process(NewReverb(cfg), impulse, testFrequency*2)
+	if energy(large[half:]) <= energy(out[half:]) {
+		t.Fatal("Larger room does not ring longer")
+	}
+
+	cfg.Wet, cfg.Dry = 0, 1
+	dry := process(NewReverb(cfg), impulse, 100)
+	for i := range dry {
+		assertNear(t, "dry impulse", dry[i], impulse(i))
+	}
+}
+
+// the largest change between two samples
+func maxStep(samples []float32) float32 {
+	step := float32(0)
+	for i := 1; i < len(samples); i++ {
+		step = max(step, abs(samples[i]-samples[i-1]))
+	}
+	return step
+}
+
+func TestEffectAutomation(t *testing.T) {
+	// the largest step of a full scale 200Hz sine
+	limit := float32(2*math.Pi*200/testFrequency) * 1.5
+
+	f := NewBiquad(LowPass, 5000, math.Sqrt2/2)
+	out := process(f, func(i int) float32 {
+		if i == testFrequency/4 {
+			f.SetCutoff(300)
+			f.SetQ(1)
+		}
+		return sine(1, 200)(i)
+	}, testFrequency/2)
+	if step := maxStep(out[1:]); step > limit {
+		t.Fatalf("Filter sweep stepped by %f", step)
+	}
+
+	d := NewDelay(0.1, 0, 1)
+	out = process(d, func(i int) float32 {
+		if i == testFrequency/4 {
+			d.SetTime(0.05)
+		}
+		return sine(1, 200)(i)
+	}, testFrequency/2)
+	if step := maxStep(out[testFrequency/10+1:]); step > limit*2 {
+		t.Fatalf("Delay time change stepped by %f", step)
+	}
+}
+
+func TestVoiceAndMasterEffects(t *testing.T) {
+	a := newTestMixer()
+	v := a.play(constantAsset(0.5, testFrequency), PlayConfig{})
+	v.AddEffect(NewBiquad(HighPass, 1000, math.Sqrt2/2))
+
+	// a high pass removes DC
+	a.mix(1000)
+	assertNear(t, "voice effect", a.masterTrack[audio.ChannelLeft][999], 0)
+
+	v.RemoveEffect(v.effects[0])
+	a.masterEffects = append(a.masterEffects, NewDelay(0.001, 0, 1))
+	a.masterEffects[0].Init(a.spec)
+	a.mix(100)
+
+	// the delay line starts silent
+	assertNear(t, "master effect", a.masterTrack[audio.ChannelLeft][0], 0)
+	assertNear(t, "master effect", a.masterTrack[audio.ChannelLeft][99], 0.5)
+}
@@ -0,0 +1,201 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mixer
+
+import (
+	"math"
+)
+
+// gain changes are spread over this many seconds so they do not click
+const rampSeconds = 0.005
+
+type Category uint8
+
+const (
+	CategorySFX Category = iota
+	CategoryMusic
+	CategoryUI
+	CategoryVoice
+	categoryCount
+)
+
+/*
+ramp linearly moves a value towards its target over a fixed number of
+samples, next must be called once per output sample.
+*/
+type ramp struct {
+	value     float32
+	target    float32
+	step      float32
+	remaining int
+}
+
+func newRamp(value float32) ramp {
+	return ramp{value: value, target: value}
+}
+
+func (r *ramp) set(target float32, samples int) {
+	r.target = target
+
+	if samples <= 0 {
+		r.value = target
+		r.remaining = 0
+		return
+	}
+
+	r.step = (target - r.value) / float32(samples)
+	r.remaining = samples
+}
+
+func (r *ramp) next() float32 {
+	if r.remaining > 0 {
+		r.remaining--
+		r.value += r.step
+
+		if r.remaining == 0 {
+			r.value = r.target
+		}
+	}
+
+	return r.value
+}
+
+// writes the next len(dst) values of the ramp to dst
+func (r *ramp) fill(dst []float32) {
+	i := 0
+	for ; i < len(dst) && r.remaining > 0; i++ {
+		dst[i] = r.next()
+	}
+
+	rest := dst[i:]
+	for i := range rest {
+		rest[i] = r.value
+	}
+}
+
+// multiplies dst by the next len(dst) values of the ramp
+func (r *ramp) mul(dst []float32) {
+	i := 0
+	for ; i < len(dst) && r.remaining > 0; i++ {
+		dst[i] *= r.next()
+	}
+
+	if r.value == 1 {
+		return
+	}
+
+	rest := dst[i:]
+	for i := range rest {
+		rest[i] *= r.value
+	}
+}
+
+/*
+returns the constant power gains for pan in [-1, 1], scaled so that the
+center is unity on both channels and hard panning is +3dB on one side.
+*/
+func panGains(pan float32) (float32, float32) {
+	pan = min(max(pan, -1), 1)
+	theta := float64(pan+1) * math.Pi / 4
+	left, right := math.Cos(theta)*math.Sqrt2, math.Sin(theta)*math.Sqrt2
+
+	// hard panning leaves a rounding error on the silent side that would turn
+	// quiet samples into denormals, which are slow to compute with
+	if left < 1e-6 {
+		left = 0
+	}
+	if right < 1e-6 {
+		right = 0
+	}
+	return float32(left), float32(right)
+}
+
+// Curve shapes a fade from one gain to another
+type Curve uint8
+
+const (
+	CurveLinear Curve = iota
+	// keeps the summed power of a crossfade constant, the usual choice for music
+	CurveEqualPower
+	// starts and ends slowly
+	CurveSmooth
+)
+
+/*
+fade moves a voice's gain along a curve between two mixer sample times, it is
+evaluated from the absolute sample time so fades can be scheduled ahead.
+*/
+type fade struct {
+	start  int64
+	length int
+	from   float32
+	to     float32
+	curve  Curve
+	// stop the voice once the fade completed
+	stop bool
+}
+
+func (f *fade) at(t int64) float32 {
+	if t >= f.start+int64(f.length) {
+		return f.to
+	}
+	if t < f.start {
+		return f.from
+	}
+
+	x := float32(t-f.start) / float32(f.length)
+
+	switch f.curve {
+	case CurveEqualPower:
+		return float32(math.Sqrt(float64(f.from*f.from + (f.to*f.to-f.from*f.from)*x)))
+	case CurveSmooth:
+		x = x * x * (3 - 2*x)
+	}
+
+	return f.from + (f.to-f.from)*x
+}
+
+// returns true once a fade with stop set completed at sample time t
+func (f *fade) done(t int64) bool {
+	return f.stop && t >= f.start+int64(f.length)
+}
+
+// multiplies dst by the fade starting at sample time t
+func (f *fade) mul(dst []float32, t int64) {
+	var gain float32
+
+	switch {
+	case t >= f.start+int64(f.length):
+		gain = f.to
+	case t+int64(len(dst)) <= f.start:
+		gain = f.from
+	default:
+		for i := range dst {
+			dst[i] *= f.at(t + int64(i))
+		}
+		return
+	}
+
+	if gain != 1 {
+		for i := range dst {
+			dst[i] *= gain
+		}
+	}
+}
@@ -0,0 +1,223 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mixer
+
+import (
+	"sync/atomic"
+)
+
+const (
+	defaultLatencySeconds = 0.1
+	// the mix buffers hold this much, well within the second the platform takes per Mix
+	maxLatencySeconds = 0.5
+	// the target grows by this after an underrun and shrinks by this while none happen
+	latencyStepSeconds = 0.01
+	// the target shrinks a step after this much audio without an underrun
+	latencyShrinkSeconds = 10
+)
+
+/*
+LatencyConfig sets how far ahead of the device the mixer fills its buffer in
+seconds. Lower latency makes sounds start sooner after they are played but
+the device runs dry when Mix is called late, so the target grows up to Max
+after every underrun and shrinks back down to Min while none happen.
+*/
+type LatencyConfig struct {
+	// 0 uses 0.1
+	Target float64
+	// 0 uses Target so the latency never drops below it
+	Min float64
+	// 0 uses 0.5, which is also the largest latency supported
+	Max float64
+}
+
+// LatencyStats describes the buffer as of the last Mix, times are in seconds
+type LatencyStats struct {
+	// how far ahead the buffer is currently filled
+	Target float64
+	// audio estimated to be queued in the device
+	Buffered float64
+	// the least audio queued when Mix was called since the last call to Latency,
+	// how close the device came to running dry
+	LowestBuffered float64
+	// times the device ran dry before Mix was called
+	Underruns uint64
+	// silence played by the device during underruns
+	Starved float64
+	// times Mix was called with the buffer already full so nothing was mixed,
+	// Mix is called more often than needed if this keeps growing
+	Overruns uint64
+}
+
+// latency tracks the estimated fill of the device buffer, only used by the audio thread
+type latency struct {
+	// limits and step sizes in samples
+	min, max    int
+	step        int
+	shrinkAfter int
+
+	target   int
+	buffered int
+	// samples played since the last underrun or shrink
+	stable int
+
+	// calls to fill since Init, the first one starts from an empty buffer
+	fills     uint64
+	underruns uint64
+	overruns  uint64
+	starved   uint64
+}
+
+// returns the config with zero fields replaced by their defaults and clamped to the supported range
+func (cfg LatencyConfig) normalized() LatencyConfig {
+	if cfg.Target <= 0 {
+		cfg.Target = defaultLatencySeconds
+	}
+	if cfg.Max <= 0 {
+		cfg.Max = maxLatencySeconds
+	}
+	if cfg.Min <= 0 {
+		cfg.Min = cfg.Target
+	}
+
+	cfg.Max = min(cfg.Max, maxLatencySeconds)
+	cfg.Target = min(cfg.Target, cfg.Max)
+	cfg.Min = min(cfg.Min, cfg.Target)
+	return cfg
+}
+
+// sets the limits, the current target is moved into them and the stats are kept
+func (l *latency) configure(cfg LatencyConfig, frequency int) {
+	cfg = cfg.normalized()
+	seconds := func(s float64) int { return max(int(s*float64(frequency)), 1) }
+
+	l.min = seconds(cfg.Min)
+	l.max = seconds(cfg.Max)
+	l.step = seconds(latencyStepSeconds)
+	l.shrinkAfter = seconds(latencyShrinkSeconds)
+	l.target = seconds(cfg.Target)
+	l.stable = 0
+}
+
+/*
+fill updates the estimated fill after the device played the given number of
+samples since the last call and returns how many samples to mix to get back to
+the target.
+*/
+func (l *latency) fill(played int) int {
+	buffered := l.buffered - max(played, 0)
+
+	if buffered < 0 {
+		l.underruns++
+		l.starved += uint64(-buffered)
+		l.target = min(l.target+l.step, l.max)
+		l.stable = 0
+		buffered = 0
+	} else {
+		l.stable += played
+		if l.stable >= l.shrinkAfter {
+			l.target = max(l.target-l.step, l.min)
+			l.stable = 0
+		}
+	}
+
+	samples := max(l.target-buffered, 0)
+	if samples == 0 {
+		l.overruns++
+	}
+
+	l.buffered = buffered + samples
+	l.fills++
+	return samples
+}
+
+// samples as published by the audio thread after every Mix
+type latencyMeter struct {
+	target   atomic.Int64
+	buffered atomic.Int64
+	// math.MaxInt64 until a fill was observed
+	lowest    atomic.Int64
+	underruns atomic.Uint64
+	overruns  atomic.Uint64
+	starved   atomic.Uint64
+}
+
+// configures the latency, stats are kept and the buffer adapts to the new target
+// over the next calls to Mix
+func SetLatency(cfg LatencyConfig) {
+	Mixer.mtx.Lock()
+	Mixer.latencyConfig = cfg
+	frequency := Mixer.spec.Frequency
+	Mixer.mtx.Unlock()
+
+	// before Init there is nothing to reconfigure, init uses the config
+	if frequency > 0 {
+		Mixer.commands.push(func() { Mixer.latency.configure(cfg, frequency) })
+	}
+}
+
+// returns the latency stats and resets LowestBuffered
+func Latency() LatencyStats {
+	return Mixer.latencyStats()
+}
+
+// publishes the fill before the device plays the block, only called by the audio thread
+func (a *audioMixer) publishLatency(before int) {
+	m := &a.published.latency
+
+	m.target.Store(int64(a.latency.target))
+	m.buffered.Store(int64(a.latency.buffered))
+	m.underruns.Store(a.latency.underruns)
+	m.overruns.Store(a.latency.overruns)
+	m.starved.Store(a.latency.starved)
+
+	// the buffer starts out empty, that is not the device running low
+	if a.latency.fills < 2 {
+		return
+	}
+	for {
+		old := m.lowest.Load()
+		if old <= int64(before) || m.lowest.CompareAndSwap(old, int64(before)) {
+			return
+		}
+	}
+}
+
+func (a *audioMixer) latencyStats() LatencyStats {
+	a.mtx.Lock()
+	frequency := float64(a.spec.Frequency)
+	a.mtx.Unlock()
+
+	if frequency == 0 {
+		return LatencyStats{}
+	}
+
+	m := &a.published.latency
+	buffered := m.buffered.Load()
+
+	return LatencyStats{
+		Target:         float64(m.target.Load()) / frequency,
+		Buffered:       float64(buffered) / frequency,
+		LowestBuffered: float64(min(m.lowest.Swap(buffered), buffered)) / frequency,
+		Underruns:      m.underruns.Load(),
+		Starved:        float64(m.starved.Load()) / frequency,
+		Overruns:       m.overruns.Load(),
+	}
+}
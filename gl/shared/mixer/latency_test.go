@@ -0,0 +1,121 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mixer
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"goarrg.com/asset/audio"
+)
+
+func TestLatencyConfig(t *testing.T) {
+	tests := []struct {
+		cfg, want LatencyConfig
+	}{
+		{LatencyConfig{}, LatencyConfig{Target: 0.1, Min: 0.1, Max: 0.5}},
+		{LatencyConfig{Target: 0.02, Min: 0.01, Max: 0.1}, LatencyConfig{Target: 0.02, Min: 0.01, Max: 0.1}},
+		{LatencyConfig{Target: 0.3, Min: 0.4, Max: 0.2}, LatencyConfig{Target: 0.2, Min: 0.2, Max: 0.2}},
+		{LatencyConfig{Target: 2}, LatencyConfig{Target: 0.5, Min: 0.5, Max: 0.5}},
+	}
+
+	for _, test := range tests {
+		if got := test.cfg.normalized(); got != test.want {
+			t.Fatalf("Normalized %+v to %+v, want %+v", test.cfg, got, test.want)
+		}
+	}
+}
+
+func TestLatencyAdapts(t *testing.T) {
+	const frequency = 1000
+
+	var l latency
+	l.configure(LatencyConfig{Target: 0.05, Min: 0.03, Max: 0.07}, frequency)
+
+	if n := l.fill(0); n != 50 {
+		t.Fatalf("Filled %d samples, want 50", n)
+	}
+	if n := l.fill(0); n != 0 || l.overruns != 1 {
+		t.Fatalf("Filled %d samples of a full buffer with %d overruns", n, l.overruns)
+	}
+
+	// every underrun grows the target by a step until the maximum
+	for i, want := range []int{60, 70, 70} {
+		if n := l.fill(l.buffered + 5); n != want {
+			t.Fatalf("Filled %d samples after underrun %d, want %d", n, i, want)
+		}
+	}
+	if l.underruns != 3 || l.starved != 15 {
+		t.Fatalf("%d underruns starved %d samples", l.underruns, l.starved)
+	}
+
+	// and it shrinks a step for every stretch without one until the minimum
+	for range 100 * latencyShrinkSeconds {
+		l.fill(frequency / 100)
+	}
+	if l.target != 60 {
+		t.Fatalf("Target at %d after %d seconds, want 60", l.target, latencyShrinkSeconds)
+	}
+	for range 1000 * latencyShrinkSeconds {
+		l.fill(frequency / 100)
+	}
+	if l.target != 30 {
+		t.Fatalf("Target at %d, want the minimum", l.target)
+	}
+}
+
+func TestLatencyStats(t *testing.T) {
+	now := time.Unix(0, 0)
+	a := &audioMixer{now: func() time.Time { return now }, limiterConfig: LimiterConfig{Disabled: true}}
+	a.init(audio.Spec{Channels: audio.ChannelsStereo(), Frequency: goldenFrequency})
+	a.lastTime = now
+
+	assertSeconds := func(what string, got, want float64) {
+		t.Helper()
+		if math.Abs(got-want) > 1e-9 {
+			t.Fatalf("%s = %f, want %f", what, got, want)
+		}
+	}
+
+	a.Mix()
+	// starting out empty is not a low fill
+	assertSeconds("lowest at start", a.latencyStats().LowestBuffered, 0.1)
+
+	now = now.Add(75 * time.Millisecond)
+	a.Mix()
+	now = now.Add(150 * time.Millisecond)
+	a.Mix()
+
+	s := a.latencyStats()
+	assertSeconds("target", s.Target, 0.11)
+	assertSeconds("buffered", s.Buffered, 0.11)
+	assertSeconds("lowest", s.LowestBuffered, 0)
+	assertSeconds("starved", s.Starved, 0.05)
+	if s.Underruns != 1 || s.Overruns != 0 {
+		t.Fatalf("%d underruns and %d overruns", s.Underruns, s.Overruns)
+	}
+
+	// the lowest fill resets on every read
+	now = now.Add(100 * time.Millisecond)
+	a.Mix()
+	assertSeconds("lowest after reset", a.latencyStats().LowestBuffered, 0.01)
+	assertSeconds("lowest after read", a.latencyStats().LowestBuffered, 0.11)
+}
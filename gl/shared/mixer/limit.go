@@ -0,0 +1,160 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mixer
+
+const (
+	defaultMaxVoices = 64
+	// stolen voices fade out over this many seconds instead of cutting off
+	stealFadeSeconds = 0.01
+)
+
+// StealPolicy picks which of the lowest priority voices is stolen
+type StealPolicy uint8
+
+const (
+	StealQuietest StealPolicy = iota
+	StealOldest
+)
+
+type VoiceStats struct {
+	// voices counting towards the limits, stolen voices still fading out are not included
+	Playing int
+	// voices faded out early to make room for a new one
+	Stolen uint64
+	// voices that never started because every playing voice had a higher priority
+	Rejected uint64
+}
+
+/*
+admit enforces the voice limits for a new voice by stealing a playing voice of
+lower or equal priority, returns false if there is no voice to steal. Only
+called by the audio thread.
+*/
+func (a *audioMixer) admit(v *Voice) bool {
+	if limit := a.instanceLimits[v.sound]; limit > 0 && v.sound != "" {
+		sameSound := func(other *Voice) bool { return other.sound == v.sound }
+		if a.countVoices(sameSound) >= limit && !a.steal(v, sameSound) {
+			return false
+		}
+	}
+
+	if a.maxVoices > 0 {
+		all := func(*Voice) bool { return true }
+		if a.countVoices(all) >= a.maxVoices && !a.steal(v, all) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// counts the voices taking up a slot that match
+func (a *audioMixer) countVoices(match func(*Voice) bool) int {
+	n := 0
+	for _, v := range a.voices {
+		if v.limited && !v.stolen && !v.stopped.Load() && match(v) {
+			n++
+		}
+	}
+	return n
+}
+
+// fades out the matching voice with the lowest priority that does not exceed
+// the new voice's, ties are broken by the steal policy
+func (a *audioMixer) steal(v *Voice, match func(*Voice) bool) bool {
+	var victim *Voice
+
+	for _, other := range a.voices {
+		if !other.limited || other.stolen || other.stopped.Load() || !match(other) || other.priority > v.priority {
+			continue
+		}
+
+		if victim == nil || other.priority < victim.priority ||
+			(other.priority == victim.priority && a.stealBefore(other, victim)) {
+			victim = other
+		}
+	}
+
+	if victim == nil {
+		return false
+	}
+
+	victim.stolen = true
+	victim.fadeAt(a.clock, 0, a.seconds(stealFadeSeconds), CurveLinear, true)
+	a.published.stolen.Add(1)
+
+	return true
+}
+
+// returns true if x should be stolen before y
+func (a *audioMixer) stealBefore(x, y *Voice) bool {
+	if a.stealPolicy == StealOldest {
+		return x.seq < y.seq
+	}
+
+	return a.loudness(x) < a.loudness(y)
+}
+
+// the gain a voice is heading to including distance attenuation, panning is ignored
+func (a *audioMixer) loudness(v *Voice) float32 {
+	return v.gain.target * v.attenuation.target * v.bus.gain.target * v.fade.to
+}
+
+// sets how many voices started with PlaySound or PlayStream can play at once,
+// 0 removes the limit. Music played by a MusicPlayer does not count.
+func SetMaxVoices(n int) {
+	Mixer.commands.push(func() {
+		Mixer.maxVoices = max(n, 0)
+	})
+}
+
+// sets how many voices of a sound can play at once, 0 removes the limit
+func SetInstanceLimit(sound string, n int) {
+	Mixer.commands.push(func() {
+		if n <= 0 {
+			delete(Mixer.instanceLimits, sound)
+			return
+		}
+
+		if Mixer.instanceLimits == nil {
+			Mixer.instanceLimits = make(map[string]int)
+		}
+		Mixer.instanceLimits[sound] = n
+	})
+}
+
+func SetStealPolicy(p StealPolicy) {
+	Mixer.commands.push(func() {
+		Mixer.stealPolicy = p
+	})
+}
+
+// returns the voice counts as of the last mixed block
+func Stats() VoiceStats {
+	return Mixer.voiceStats()
+}
+
+func (a *audioMixer) voiceStats() VoiceStats {
+	return VoiceStats{
+		Playing:  int(a.published.playing.Load()),
+		Stolen:   a.published.stolen.Load(),
+		Rejected: a.published.rejected.Load(),
+	}
+}
@@ -0,0 +1,119 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mixer
+
+import (
+	"testing"
+
+	"goarrg.com/asset/audio"
+)
+
+func TestStealQuietest(t *testing.T) {
+	a := newTestMixer()
+	a.maxVoices = 2
+
+	loud := a.play(constantAsset(0.25, 10000), PlayConfig{})
+	quiet := a.play(constantAsset(0.25, 10000), PlayConfig{Gain: 0.5})
+	a.mix(10)
+
+	v := a.play(constantAsset(0.25, 10000), PlayConfig{})
+	if !v.Playing() || !loud.Playing() {
+		t.Fatal("Stole the wrong voice")
+	}
+
+	// the stolen voice fades out instead of cutting off
+	a.mix(1)
+	assertNear(t, "first sample after steal", a.masterTrack[audio.ChannelLeft][0], 0.25+0.125+0.25)
+
+	a.mix(a.seconds(stealFadeSeconds))
+	if quiet.Playing() {
+		t.Fatal("Stolen voice still playing after its fade")
+	}
+
+	if stats := a.voiceStats(); stats.Playing != 2 || stats.Stolen != 1 || stats.Rejected != 0 {
+		t.Fatalf("Stats %+v", stats)
+	}
+	if len(a.voices) != 2 {
+		t.Fatalf("%d voices mixed, want 2", len(a.voices))
+	}
+}
+
+func TestStealOldest(t *testing.T) {
+	a := newTestMixer()
+	a.stealPolicy = StealOldest
+	a.instanceLimits = map[string]int{"a": 2}
+
+	var voices []*Voice
+	for range 3 {
+		v := newVoice(a, constantAsset(0.25, 10000), PlayConfig{})
+		v.sound = "a"
+		a.add(v)
+		voices = append(voices, v)
+	}
+
+	other := newVoice(a, constantAsset(0.25, 10000), PlayConfig{})
+	other.sound = "b"
+	a.add(other)
+	// voices are admitted by the audio thread
+	a.commands.drain()
+
+	if !voices[0].stolen || voices[1].stolen || voices[2].stolen || other.stolen {
+		t.Fatal("Did not steal the oldest instance of the sound")
+	}
+}
+
+func TestRejectLowPriority(t *testing.T) {
+	a := newTestMixer()
+	a.maxVoices = 1
+
+	high := a.play(constantAsset(0.25, 10000), PlayConfig{Priority: 1})
+	low := a.play(constantAsset(0.25, 10000), PlayConfig{Priority: 0})
+	a.mix(1)
+
+	if low.Playing() || !high.Playing() {
+		t.Fatal("Low priority voice replaced a higher priority one")
+	}
+
+	// controls on a rejected voice are no-ops
+	low.SetGain(1)
+	low.Resume()
+
+	if stats := a.voiceStats(); stats.Playing != 1 || stats.Stolen != 0 || stats.Rejected != 1 {
+		t.Fatalf("Stats %+v", stats)
+	}
+}
+
+func TestVoiceLimitBoundsMix(t *testing.T) {
+	a := newTestMixer()
+	a.maxVoices = 8
+
+	for range 1000 {
+		a.play(constantAsset(0.01, 10000), PlayConfig{})
+		a.mix(1)
+	}
+
+	// stolen voices keep fading for a few samples so allow some headroom
+	if len(a.voices) > 8+a.seconds(stealFadeSeconds) {
+		t.Fatalf("%d voices mixed with a limit of 8", len(a.voices))
+	}
+	if stats := a.voiceStats(); stats.Playing != 8 || stats.Stolen != 992 {
+		t.Fatalf("Stats %+v", stats)
+	}
+}
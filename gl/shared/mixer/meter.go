@@ -0,0 +1,130 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mixer
+
+import (
+	"math"
+	"sync/atomic"
+)
+
+// RMS is averaged over roughly this many seconds like a VU meter
+const rmsSeconds = 0.3
+
+// Level is a linear signal level across every channel, 1 is full scale
+type Level struct {
+	// largest sample since the level was last read
+	Peak float32
+	// average level over the last 300ms
+	RMS float32
+}
+
+/*
+meter measures a signal on the audio thread and publishes its level after
+every block, reading the level never blocks the audio thread.
+*/
+type meter struct {
+	// audio thread state of the block being measured
+	peak    float32
+	sum     float32
+	samples int
+	// smoothed mean square of past blocks
+	meanSquare float32
+
+	publishedPeak atomic.Uint32
+	publishedRMS  atomic.Uint32
+}
+
+// measures a block of samples, only called by the audio thread
+func (m *meter) add(samples []float32) {
+	// squares are summed in four independent lanes so the additions do not
+	// wait on each other, comparing squares saves taking the magnitude
+	var s0, s1, s2, s3, p0, p1, p2, p3 float32
+
+	i := 0
+	for ; i+4 <= len(samples); i += 4 {
+		b := samples[i : i+4 : i+4]
+		q0, q1, q2, q3 := b[0]*b[0], b[1]*b[1], b[2]*b[2], b[3]*b[3]
+		s0, s1, s2, s3 = s0+q0, s1+q1, s2+q2, s3+q3
+		if q0 > p0 {
+			p0 = q0
+		}
+		if q1 > p1 {
+			p1 = q1
+		}
+		if q2 > p2 {
+			p2 = q2
+		}
+		if q3 > p3 {
+			p3 = q3
+		}
+	}
+	for _, sample := range samples[i:] {
+		q := sample * sample
+		s0 += q
+		if q > p0 {
+			p0 = q
+		}
+	}
+
+	peak := float32(math.Sqrt(float64(max(p0, p1, p2, p3))))
+	m.peak = max(m.peak, peak)
+	m.sum += (s0 + s1) + (s2 + s3)
+	m.samples += len(samples)
+}
+
+// publishes the level of the samples measured since the last call, channels is
+// how many samples were measured per frame
+func (m *meter) publish(channels, frequency int) {
+	if m.samples == 0 || channels == 0 {
+		return
+	}
+
+	frames := float64(m.samples / channels)
+	coef := float32(1 - math.Exp(-frames/(rmsSeconds*float64(frequency))))
+	m.meanSquare += (m.sum/float32(m.samples) - m.meanSquare) * coef
+
+	storeMax(&m.publishedPeak, m.peak)
+	m.publishedRMS.Store(math.Float32bits(float32(math.Sqrt(float64(m.meanSquare)))))
+
+	m.peak, m.sum, m.samples = 0, 0, 0
+}
+
+// returns the published level and resets the peak, safe to call from any goroutine
+func (m *meter) level() Level {
+	return Level{
+		Peak: math.Float32frombits(m.publishedPeak.Swap(0)),
+		RMS:  math.Float32frombits(m.publishedRMS.Load()),
+	}
+}
+
+// returns the level of everything played on the bus after its gain
+func (b *Bus) Level() Level {
+	return b.meter.level()
+}
+
+// returns the level the voice adds to its bus after gain and pan
+func (v *Voice) Level() Level {
+	return v.meter.level()
+}
+
+// returns the level of the final mix after the limiter
+func MasterLevel() Level {
+	return Mixer.masterMeter.level()
+}
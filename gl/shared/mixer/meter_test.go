@@ -0,0 +1,74 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mixer
+
+import (
+	"testing"
+)
+
+func TestMeters(t *testing.T) {
+	a := newTestMixer()
+	sfx := a.categoryBuses[CategorySFX]
+	music := a.categoryBuses[CategoryMusic]
+
+	v := a.play(constantAsset(0.5, testFrequency), PlayConfig{Gain: 0.5})
+	v.SetLoop(LoopForever)
+	a.play(constantAsset(0.25, testFrequency), PlayConfig{Category: CategoryMusic}).SetLoop(LoopForever)
+	sfx.SetGain(0.5)
+
+	// long enough for the RMS to settle, the peaks of the gain ramp are read and reset
+	for range 40 {
+		a.mix(a.bufferSamples)
+	}
+	v.Level()
+	sfx.Level()
+	a.masterMeter.level()
+	a.mix(a.bufferSamples)
+
+	tests := []struct {
+		name      string
+		level     Level
+		peak, rms float32
+	}{
+		{"voice", v.Level(), 0.25, 0.25},
+		{"sfx", sfx.Level(), 0.125, 0.125},
+		{"music", music.Level(), 0.25, 0.25},
+		{"master", a.masterMeter.level(), 0.375, 0.375},
+	}
+
+	for _, test := range tests {
+		assertNear(t, test.name+" peak", test.level.Peak, test.peak)
+		assertNear(t, test.name+" rms", test.level.RMS, test.rms)
+	}
+
+	// the peak is reset by reading it while the RMS is kept
+	if l := v.Level(); l.Peak != 0 || l.RMS == 0 {
+		t.Fatalf("Level after reading %+v", l)
+	}
+
+	// a silent bus decays instead of holding its last level
+	sfx.SetMute(true)
+	for range 20 {
+		a.mix(a.bufferSamples)
+	}
+	if l := sfx.Level(); l.RMS > 0.01 {
+		t.Fatalf("Muted bus level %+v", l)
+	}
+}
@@ -0,0 +1,547 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2020 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mixer
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"goarrg.com"
+	"goarrg.com/asset/audio"
+	"goarrg.com/debug"
+)
+
+/*
+audioMixer is split between the game thread and the audio thread calling Mix.
+The game thread never touches the audio thread's state directly, it posts
+commands that Mix runs at the start of the next block so Mix never waits on a
+lock held by the game.
+*/
+type audioMixer struct {
+	// game thread state, guarded by mtx which Mix never takes
+	mtx sync.Mutex
+	// only written by init before the first Mix
+	spec      audio.Spec
+	musicFile string
+	streams   []*streamSource
+	players   []*MusicPlayer
+	clocks    []*BeatClock
+	// every bus and the routing between them, the audio thread only sees busOrder
+	buses         []*Bus
+	categoryBuses [categoryCount]*Bus
+	// picks random pitches, created on first use
+	rand *rand.Rand
+
+	resampleQuality  ResampleQuality
+	limiterConfig    LimiterConfig
+	compressorConfig CompressorConfig
+	latencyConfig    LatencyConfig
+
+	commands commandQueue
+
+	// audio thread state, only touched by Mix and the commands it runs
+	voices      []*Voice
+	masterTrack audio.Track
+	// the most samples a block can hold
+	bufferSamples int
+	lastTime      time.Time
+	latency       latency
+	// returns the wall clock time Mix paces itself by, time.Now if nil
+	now func() time.Time
+	// samples mixed since Init, voices and fades are scheduled against it
+	clock int64
+
+	// the output has both front channels so voices can be panned
+	stereo bool
+
+	listener Listener
+
+	rampSamples int
+	masterGain  ramp
+	// buses in the order they are mixed, see sortBuses
+	busOrder []*Bus
+	// holds the frames read from a voice's source
+	voiceBlock [][]float32
+	// the part of voiceBlock the voice plays in this block, voiceBlock keeps its full length
+	voiceView [][]float32
+	blocks    blocks
+
+	maxVoices      int
+	instanceLimits map[string]int
+	stealPolicy    StealPolicy
+	voiceSeq       uint64
+
+	masterEffects []Effect
+	limiter       limiter
+	compressor    compressor
+	masterMeter   meter
+
+	// published by the audio thread after every block for the game thread
+	published struct {
+		clock    atomic.Int64
+		playing  atomic.Int64
+		stolen   atomic.Uint64
+		rejected atomic.Uint64
+		dynamics dynamicsMeter
+		latency  latencyMeter
+	}
+}
+
+var Mixer = &audioMixer{maxVoices: defaultMaxVoices}
+
+func Setup(music string) error {
+	Mixer.musicFile = music
+	return nil
+}
+
+func (a *audioMixer) AudioConfig() goarrg.AudioConfig {
+	return goarrg.AudioConfig{
+		Spec: audio.Spec{
+			Channels:  audio.ChannelsStereo(),
+			Frequency: 44100,
+		},
+	}
+}
+
+func (a *audioMixer) Init(_ goarrg.PlatformInterface, cfg goarrg.AudioConfig) error {
+	a.init(cfg.Spec)
+
+	a.lastTime = a.time()
+
+	if a.musicFile != "" {
+		DefaultMusicPlayer.SetPlaylist(MusicConfig{}, MusicTrack{File: a.musicFile, Loops: LoopForever})
+		return DefaultMusicPlayer.Play()
+	}
+
+	return nil
+}
+
+func (a *audioMixer) time() time.Time {
+	if a.now != nil {
+		return a.now()
+	}
+	return time.Now()
+}
+
+// returns the spec given to Init, or the requested one if called before Init
+func (a *audioMixer) outputSpec() audio.Spec {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	if a.spec.Frequency == 0 {
+		return a.AudioConfig().Spec
+	}
+	return a.spec
+}
+
+// converts a loaded asset to the output frequency so voices can play it sample by sample
+func (a *audioMixer) convert(s audio.Asset) audio.Asset {
+	a.mtx.Lock()
+	quality := a.resampleQuality
+	a.mtx.Unlock()
+
+	return Resample(s, a.outputSpec().Frequency, quality)
+}
+
+// allocates the mix buffers for the given output spec, called before the first Mix
+func (a *audioMixer) init(spec audio.Spec) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	a.spec = spec
+	a.masterTrack = make(audio.Track)
+	a.bufferSamples = a.seconds(maxLatencySeconds)
+	a.latency.configure(a.latencyConfig, spec.Frequency)
+	a.published.latency.target.Store(int64(a.latency.target))
+	a.published.latency.lowest.Store(math.MaxInt64)
+	a.rampSamples = int(rampSeconds * float64(spec.Frequency))
+	a.stereo = slices.Contains(spec.Channels, audio.ChannelLeft) && slices.Contains(spec.Channels, audio.ChannelRight)
+
+	for _, c := range a.spec.Channels {
+		a.masterTrack[c] = make([]float32, a.bufferSamples)
+	}
+	a.blocks.init(a.bufferSamples)
+
+	a.masterGain = newRamp(1)
+	a.defaultBuses()
+	for _, b := range a.buses {
+		b.init(spec, a.bufferSamples)
+	}
+
+	for _, e := range a.masterEffects {
+		e.Init(spec)
+	}
+
+	a.limiter.configure(a.limiterConfig, spec.Frequency, len(spec.Channels))
+	a.compressor.configure(a.compressorConfig, spec.Frequency)
+}
+
+/*
+Mix refills the device's buffer up to the latency target, what the device
+played since the last call is estimated from the wall clock as the platform
+does not report it. A new latency config takes effect from the next call as
+the commands are run by mix. As the block size depends on when Mix is called
+its output is not reproducible, use RenderWAV for deterministic output.
+*/
+func (a *audioMixer) Mix() (int, audio.Track) {
+	now := a.time()
+	played := int(now.Sub(a.lastTime).Seconds() * float64(a.spec.Frequency))
+	a.lastTime = now
+
+	samples := a.latency.fill(played)
+	a.publishLatency(a.latency.buffered - samples)
+
+	a.mix(samples)
+
+	return samples, a.masterTrack
+}
+
+// mixes the next samples of every voice and bus into the start of masterTrack after
+// running the commands posted since the last block, only called by the audio thread
+func (a *audioMixer) mix(samples int) {
+	a.commands.drain()
+
+	// drop voices stopped from the game thread before they are mixed again
+	for i := 0; i < len(a.voices); {
+		if a.voices[i].stopped.Load() {
+			a.removeVoice(i)
+		} else {
+			i++
+		}
+	}
+
+	for _, c := range a.spec.Channels {
+		clear(a.masterTrack[c][:samples])
+		for _, b := range a.busOrder {
+			clear(b.track[c][:samples])
+		}
+	}
+	a.updateSolo()
+
+	for i := 0; i < len(a.voices); {
+		v := a.voices[i]
+		playing := a.mixVoice(v, samples)
+		v.meter.publish(len(v.routes), a.spec.Frequency)
+
+		if playing {
+			i++
+		} else {
+			a.voices[i].stopped.Store(true)
+			a.removeVoice(i)
+		}
+	}
+
+	for _, b := range a.busOrder {
+		b.process(samples)
+	}
+
+	gains := a.blocks.gain[:samples]
+	a.masterGain.fill(gains)
+	for _, c := range a.spec.Channels {
+		mulBlock(a.masterTrack[c][:samples], gains)
+	}
+
+	for _, e := range a.masterEffects {
+		e.Process(a.masterTrack, samples)
+	}
+
+	a.compressor.process(a.masterTrack, a.spec.Channels, samples)
+	a.limiter.process(a.masterTrack, a.spec.Channels, samples)
+
+	for _, c := range a.spec.Channels {
+		a.masterMeter.add(a.masterTrack[c][:samples])
+	}
+	a.masterMeter.publish(len(a.spec.Channels), a.spec.Frequency)
+
+	a.clock += int64(samples)
+	a.publish()
+}
+
+// makes the state the game thread can query visible to it
+func (a *audioMixer) publish() {
+	a.published.clock.Store(a.clock)
+	a.published.playing.Store(int64(a.countVoices(func(*Voice) bool { return true })))
+	a.publishDynamics()
+}
+
+// adds the next samples of the voice to the track of its bus, returns false once the
+// voice has ended
+func (a *audioMixer) mixVoice(v *Voice, samples int) bool {
+	if v.paused.Load() {
+		return true
+	}
+
+	// voices scheduled ahead start part way into the block
+	offset := int(min(max(v.startAt-a.clock, 0), int64(samples)))
+	if offset == samples {
+		return true
+	}
+
+	if v.spatial != nil {
+		a.spatialize(v)
+	}
+
+	channels := len(v.src.channels())
+	for len(a.voiceBlock) < channels {
+		a.voiceBlock = append(a.voiceBlock, make([]float32, a.bufferSamples))
+		a.voiceView = append(a.voiceView, nil)
+	}
+
+	block := a.voiceView[:channels]
+	for c := range block {
+		block[c] = a.voiceBlock[c][:samples-offset]
+	}
+
+	n := offset + v.src.read(block)
+	if len(v.effects) > 0 {
+		v.processEffects(block, n-offset)
+	}
+
+	// a fade stopping the voice cuts the block short
+	end := n
+	if v.fade.stop {
+		end = int(min(max(v.fade.start+int64(v.fade.length)-a.clock, int64(offset)), int64(n)))
+	}
+	frames := end - offset
+
+	gains := a.blocks.gain[:frames]
+	v.gain.fill(gains)
+	v.attenuation.mul(gains)
+	v.fade.mul(gains, a.clock+int64(offset))
+
+	// panning only makes sense with both front channels
+	left, right := gains, gains
+	if a.stereo {
+		left, right = a.blocks.left[:frames], a.blocks.right[:frames]
+		copy(left, gains)
+		copy(right, gains)
+		v.panLeft.mul(left)
+		v.panRight.mul(right)
+	}
+
+	out := v.bus.track
+	routed := a.blocks.route[:frames]
+
+	for _, r := range v.routes {
+		switch r.out {
+		case audio.ChannelLeft:
+			scaleBlock(routed, block[r.in], left, r.gain)
+		case audio.ChannelRight:
+			scaleBlock(routed, block[r.in], right, r.gain)
+		default:
+			scaleBlock(routed, block[r.in], gains, r.gain)
+		}
+
+		addBlock(out[r.out][offset:end], routed)
+		v.meter.add(routed)
+	}
+
+	return end == samples && !v.src.ended() && !v.fade.done(a.clock+int64(samples))
+}
+
+// converts seconds to samples at the output frequency
+func (a *audioMixer) seconds(s float64) int {
+	return int(s * float64(a.spec.Frequency))
+}
+
+func (a *audioMixer) removeVoice(i int) {
+	if v := a.voices[i]; v.release != nil {
+		v.release()
+		v.release = nil
+	}
+
+	a.voices = append(a.voices[:i], a.voices[i+1:]...)
+}
+
+// schedules music transitions, reports beats, wakes the stream decoders that are running low,
+// reports underruns and passes on commands that did not fit in the queue
+func (a *audioMixer) Update() {
+	a.mtx.Lock()
+	players := slices.Clone(a.players)
+	clocks := slices.Clone(a.clocks)
+	a.mtx.Unlock()
+
+	for _, p := range players {
+		p.update()
+	}
+	for _, c := range clocks {
+		c.update()
+	}
+
+	a.commands.flush()
+
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	// voices close their stream on the audio thread when they are dropped
+	a.streams = slices.DeleteFunc(a.streams, (*streamSource).closed)
+
+	for _, s := range a.streams {
+		s.requestRefill()
+
+		if underruns := s.underruns.Load(); underruns != s.reported {
+			debug.WPrintf("Stream %q underrun, played %d frames of silence", s.name, underruns-s.reported)
+			s.reported = underruns
+		}
+	}
+}
+
+func (a *audioMixer) Destroy() {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	for _, s := range a.streams {
+		s.close()
+	}
+	a.streams = nil
+}
+
+// starts playing a sound once as a sound effect, use the returned voice to control it.
+// The sound is cached in DefaultSoundBank so it is only decoded the first time.
+// If the voice limits are reached and no voice can be stolen the voice returned
+// is stopped by the mixer before it plays.
+func PlaySound(sound string) (*Voice, error) {
+	return PlaySoundWithConfig(sound, PlayConfig{})
+}
+
+/*
+PlayAt plays a sound once starting exactly on the given mixer sample time, for
+example a beat from a BeatClock. Times that were already mixed start with the
+next block.
+*/
+func PlayAt(sound string, sampleTime int64) (*Voice, error) {
+	return PlaySoundWithConfig(sound, PlayConfig{StartAt: sampleTime})
+}
+
+// returns the mixer sample time of the next sample Mix produces
+func SampleTime() int64 {
+	return Mixer.published.clock.Load()
+}
+
+// same as PlaySound but with the initial voice settings given by cfg
+func PlaySoundWithConfig(sound string, cfg PlayConfig) (*Voice, error) {
+	return DefaultSoundBank.Play(sound, cfg)
+}
+
+/*
+PlayStream plays a WAV file decoded in chunks on a background goroutine
+instead of all at once, use it for long sounds like music or ambience that
+would take a lot of memory once decoded.
+*/
+func PlayStream(file string, cfg PlayConfig) (*Voice, error) {
+	v, err := Mixer.stream(file, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	v.sound = file
+	Mixer.add(v)
+	return v, nil
+}
+
+// opens the file as a stream and returns a voice for it that is not playing yet
+func (a *audioMixer) stream(file string, cfg PlayConfig) (*Voice, error) {
+	a.mtx.Lock()
+	quality := a.resampleQuality
+	a.mtx.Unlock()
+
+	s, err := openStream(file, a.outputSpec().Frequency, quality)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.streamVoice(s, cfg), nil
+}
+
+// starts decoding the stream and returns a voice for it that is not playing yet
+func (a *audioMixer) streamVoice(s *streamSource, cfg PlayConfig) *Voice {
+	s.start()
+
+	v := newSourceVoice(a, s, cfg)
+	v.release = s.close
+
+	a.mtx.Lock()
+	a.streams = append(a.streams, s)
+	a.mtx.Unlock()
+
+	return v
+}
+
+func (a *audioMixer) play(s audio.Asset, cfg PlayConfig) *Voice {
+	v := newVoice(a, s, cfg)
+	a.add(v)
+	return v
+}
+
+// adds a voice that counts towards the voice limits, it is stopped at the start of
+// the next block if it does not fit
+func (a *audioMixer) add(v *Voice) {
+	v.limited = true
+
+	a.commands.push(func() {
+		a.voiceSeq++
+		v.seq = a.voiceSeq
+
+		if !a.admit(v) {
+			a.published.rejected.Add(1)
+			v.stopped.Store(true)
+
+			if v.release != nil {
+				v.release()
+				v.release = nil
+			}
+			return
+		}
+
+		if v.spatial != nil {
+			a.place(v)
+		}
+		a.voices = append(a.voices, v)
+	})
+}
+
+// returns the voice of the track DefaultMusicPlayer is playing, the music given
+// to Setup loops forever by default
+func Music() *Voice {
+	return DefaultMusicPlayer.Voice()
+}
+
+// sets the quality used to convert sounds loaded from now on to the output frequency
+func SetResampleQuality(q ResampleQuality) {
+	Mixer.mtx.Lock()
+	Mixer.resampleQuality = q
+	Mixer.mtx.Unlock()
+}
+
+// sets the gain applied to the final mix, changes are ramped to avoid clicks
+func SetMasterGain(gain float32) {
+	Mixer.commands.push(func() {
+		Mixer.masterGain.set(max(gain, 0), Mixer.rampSamples)
+	})
+}
+
+// sets the gain of the bus of a category, changes are ramped to avoid clicks
+func SetCategoryGain(c Category, gain float32) {
+	CategoryBus(c).SetGain(gain)
+}
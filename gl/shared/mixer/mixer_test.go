@@ -0,0 +1,264 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mixer
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"goarrg.com/asset/audio"
+)
+
+const testFrequency = 44100
+
+// returns a stereo asset where every sample is value
+func constantAsset(value float32, samples int) audio.Asset {
+	spec := audio.Spec{Channels: audio.ChannelsStereo(), Frequency: testFrequency}
+	track := make(audio.Track)
+
+	for _, c := range spec.Channels {
+		track[c] = make([]float32, samples)
+		for i := range track[c] {
+			track[c][i] = value
+		}
+	}
+
+	return newTrackAsset(spec, track)
+}
+
+// returns a mixer with the limiter disabled so samples come out without delay
+func newTestMixer() *audioMixer {
+	a := &audioMixer{limiterConfig: LimiterConfig{Disabled: true}}
+	a.init(audio.Spec{Channels: audio.ChannelsStereo(), Frequency: testFrequency})
+	return a
+}
+
+func abs(f float32) float32 {
+	return float32(math.Abs(float64(f)))
+}
+
+func assertNear(t *testing.T, what string, got, want float32) {
+	t.Helper()
+
+	if math.Abs(float64(got-want)) > 1e-5 {
+		t.Fatalf("%s = %f, want %f", what, got, want)
+	}
+}
+
+func TestMixGainAndPan(t *testing.T) {
+	tests := []struct {
+		cfg         PlayConfig
+		left, right float32
+	}{
+		{PlayConfig{}, 0.25, 0.25},
+		{PlayConfig{Gain: 0.5}, 0.125, 0.125},
+		{PlayConfig{Pan: -1}, 0.25 * math.Sqrt2, 0},
+		{PlayConfig{Pan: 1, Gain: 2}, 0, 0.5 * math.Sqrt2},
+	}
+
+	for _, test := range tests {
+		a := newTestMixer()
+		a.play(constantAsset(0.25, 100), test.cfg)
+		a.mix(10)
+
+		assertNear(t, "left", a.masterTrack[audio.ChannelLeft][9], test.left)
+		assertNear(t, "right", a.masterTrack[audio.ChannelRight][9], test.right)
+	}
+}
+
+func TestPanConstantPower(t *testing.T) {
+	for pan := float32(-1); pan <= 1; pan += 0.125 {
+		l, r := panGains(pan)
+		assertNear(t, "power", l*l+r*r, 2)
+	}
+}
+
+func TestMixGainRamp(t *testing.T) {
+	a := newTestMixer()
+	v := a.play(constantAsset(0.5, testFrequency), PlayConfig{})
+
+	a.mix(1)
+	v.SetGain(0)
+	a.mix(a.rampSamples + 1)
+
+	left := a.masterTrack[audio.ChannelLeft]
+	maxStep := 0.5/float32(a.rampSamples) + 1e-6
+
+	for i := 1; i < a.rampSamples; i++ {
+		if left[i] > left[i-1] || left[i-1]-left[i] > maxStep {
+			t.Fatalf("Gain ramp jumped from %f to %f at %d", left[i-1], left[i], i)
+		}
+	}
+
+	assertNear(t, "ramp start", left[0], 0.5-0.5/float32(a.rampSamples))
+	assertNear(t, "ramp end", left[a.rampSamples], 0)
+}
+
+func TestMixCategoryAndMasterGain(t *testing.T) {
+	a := newTestMixer()
+	a.play(constantAsset(0.5, 10000), PlayConfig{Category: CategoryMusic})
+	a.play(constantAsset(0.25, 10000), PlayConfig{Category: CategoryUI})
+
+	a.categoryBuses[CategoryMusic].gain.set(0.5, 0)
+	a.masterGain.set(0.5, 0)
+	a.mix(10)
+
+	assertNear(t, "mix", a.masterTrack[audio.ChannelLeft][0], (0.5*0.5+0.25)*0.5)
+}
+
+func TestMixClip(t *testing.T) {
+	a := newTestMixer()
+	a.play(constantAsset(0.75, 10), PlayConfig{})
+	a.play(constantAsset(0.75, 10), PlayConfig{})
+	a.play(constantAsset(-1, 20), PlayConfig{Gain: 2})
+	a.mix(20)
+
+	assertNear(t, "clip", a.masterTrack[audio.ChannelLeft][0], -0.5)
+	assertNear(t, "clip", a.masterTrack[audio.ChannelLeft][15], -1)
+
+	a.play(constantAsset(0.75, 10), PlayConfig{})
+	a.play(constantAsset(0.75, 10), PlayConfig{})
+	a.mix(20)
+
+	assertNear(t, "clip", a.masterTrack[audio.ChannelLeft][0], 1)
+	assertNear(t, "after end", a.masterTrack[audio.ChannelLeft][15], 0)
+
+	if len(a.voices) != 0 {
+		t.Fatalf("%d voices still playing", len(a.voices))
+	}
+}
+
+// returns a mixer with voices looping forever whose Mix is called every block samples
+func newLoadedMixer(voices, block int) *audioMixer {
+	now := time.Unix(0, 0)
+	a := &audioMixer{now: func() time.Time { return now }, maxVoices: voices}
+	a.init(audio.Spec{Channels: audio.ChannelsStereo(), Frequency: testFrequency})
+	a.lastTime = now
+
+	step := time.Duration(block) * time.Second / testFrequency
+	a.now = func() time.Time {
+		now = now.Add(step)
+		return now
+	}
+
+	asset := constantAsset(0.001, testFrequency)
+	for i := range voices {
+		v := a.play(asset, PlayConfig{Category: Category(i % int(categoryCount)), Pan: float32(i%5)/2 - 1})
+		v.SetLoop(LoopForever)
+	}
+	a.Mix()
+
+	return a
+}
+
+func TestMixAllocations(t *testing.T) {
+	a := newLoadedMixer(128, 512)
+
+	// solo and the filter effects run every block
+	music := a.categoryBuses[CategoryMusic]
+	music.SetSolo(true)
+	music.AddEffect(NewBiquad(LowPass, 1000, 0.7))
+	music.AddEffect(NewDelay(0.1, 0.5, 0.5))
+	a.Mix()
+
+	if allocs := testing.AllocsPerRun(100, func() { a.Mix() }); allocs != 0 {
+		t.Fatalf("Mix allocated %f times per call", allocs)
+	}
+}
+
+func BenchmarkMix(b *testing.B) {
+	for _, voices := range []int{64, 128, 256} {
+		b.Run(fmt.Sprintf("voices=%d", voices), func(b *testing.B) {
+			a := newLoadedMixer(voices, 512)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				a.Mix()
+			}
+			b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*512), "ns/frame")
+		})
+	}
+}
+
+// compares mixing a block of a stereo voice sample by sample through the track's
+// map like Mix used to against the block loops
+func BenchmarkMixVoiceLoop(b *testing.B) {
+	const samples = 512
+
+	channels := audio.ChannelsStereo()
+	in := [][]float32{make([]float32, samples), make([]float32, samples)}
+	out := make(audio.Track)
+	for i, c := range channels {
+		out[c] = make([]float32, samples)
+		for j := range in[i] {
+			in[i][j] = float32(j) / samples
+		}
+	}
+	routes := channelRoutes(channels, channels)
+	gain, panLeft, panRight := newRamp(0.5), newRamp(0.8), newRamp(1.2)
+
+	b.Run("per-sample", func(b *testing.B) {
+		var m meter
+		for i := 0; i < b.N; i++ {
+			for j := 0; j < samples; j++ {
+				g := gain.next()
+				left, right := g*panLeft.next(), g*panRight.next()
+
+				for _, r := range routes {
+					sample := in[r.in][j] * r.gain
+					if r.out == audio.ChannelLeft {
+						sample *= left
+					} else {
+						sample *= right
+					}
+					out[r.out][j] += sample
+					m.add([]float32{sample})
+				}
+			}
+		}
+	})
+
+	b.Run("block", func(b *testing.B) {
+		var m meter
+		var blocks blocks
+		blocks.init(samples)
+
+		for i := 0; i < b.N; i++ {
+			gain.fill(blocks.gain)
+			copy(blocks.left, blocks.gain)
+			copy(blocks.right, blocks.gain)
+			panLeft.mul(blocks.left)
+			panRight.mul(blocks.right)
+
+			for _, r := range routes {
+				gains := blocks.right
+				if r.out == audio.ChannelLeft {
+					gains = blocks.left
+				}
+				scaleBlock(blocks.route, in[r.in], gains, r.gain)
+				addBlock(out[r.out], blocks.route)
+				m.add(blocks.route)
+			}
+		}
+	})
+}
@@ -0,0 +1,352 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mixer
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"goarrg.com/asset/audio"
+	"goarrg.com/debug"
+)
+
+// transitions are scheduled this far ahead so the next track is ready in time
+const musicLookaheadSeconds = 2
+
+type MusicTrack struct {
+	File string
+	// the section repeated by Loops in samples of the file, everything before
+	// LoopStart plays once as an intro. LoopEnd 0 is the end of the file.
+	LoopStart int
+	LoopEnd   int
+	// times the loop section repeats before the player moves on, LoopForever
+	// stays on the track until Next or Play is called
+	Loops int
+}
+
+type MusicConfig struct {
+	// seconds the outgoing and incoming tracks overlap, 0 switches without a gap
+	Crossfade float64
+	FadeIn    Curve
+	FadeOut   Curve
+	Shuffle   bool
+	// starts the playlist over after the last track, otherwise the music stops
+	Repeat bool
+}
+
+/*
+MusicPlayer plays a playlist of tracks on the music category, transitions are
+scheduled at exact mixer sample times so they do not depend on when Update runs.
+*/
+type MusicPlayer struct {
+	mtx    sync.Mutex
+	mixer  *audioMixer
+	cfg    MusicConfig
+	tracks []MusicTrack
+	// play order of tracks, shuffled if enabled
+	order []int
+	pos   int
+
+	current *Voice
+	// mixer sample time the current track ends at, -1 if it loops forever
+	end int64
+	// the transition after the current track was already scheduled
+	scheduled bool
+
+	rand   *rand.Rand
+	load   func(string) (audio.Asset, error)
+	stream func(string) (*streamSource, error)
+}
+
+// plays the music given to Setup
+var DefaultMusicPlayer = NewMusicPlayer()
+
+func NewMusicPlayer() *MusicPlayer {
+	return newMusicPlayer(Mixer)
+}
+
+func newMusicPlayer(a *audioMixer) *MusicPlayer {
+	p := &MusicPlayer{
+		mixer: a,
+		rand:  rand.New(rand.NewSource(time.Now().UnixNano())),
+		load:  audio.Load,
+		stream: func(file string) (*streamSource, error) {
+			a.mtx.Lock()
+			quality := a.resampleQuality
+			a.mtx.Unlock()
+
+			return openStream(file, a.outputSpec().Frequency, quality)
+		},
+	}
+
+	a.mtx.Lock()
+	a.players = append(a.players, p)
+	a.mtx.Unlock()
+
+	return p
+}
+
+// replaces the playlist, the track playing continues until Play or Next is called
+func (p *MusicPlayer) SetPlaylist(cfg MusicConfig, tracks ...MusicTrack) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.cfg = cfg
+	p.tracks = append([]MusicTrack(nil), tracks...)
+	p.shuffle(-1)
+	p.pos = -1
+}
+
+// starts the playlist from the first track, crossfading from the current one
+func (p *MusicPlayer) Play() error {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.shuffle(-1)
+	return p.transition(p.now(), 0)
+}
+
+// crossfades to the next track in the playlist now
+func (p *MusicPlayer) Next() error {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	pos, ok := p.nextPos()
+	if !ok {
+		return debug.Errorf("No track after the last one, enable Repeat to wrap around")
+	}
+
+	return p.transition(p.now(), pos)
+}
+
+// fades out the current track with the FadeOut curve and stops the playlist
+func (p *MusicPlayer) Stop(seconds float64) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if p.current != nil {
+		p.current.FadeOut(seconds, p.cfg.FadeOut)
+	}
+
+	p.current = nil
+	p.scheduled = false
+}
+
+// returns the voice of the track playing, or nil if the music stopped
+func (p *MusicPlayer) Voice() *Voice {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	return p.current
+}
+
+// returns the playlist index of the track playing, or -1 if the music stopped
+func (p *MusicPlayer) Track() int {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if p.current == nil || p.pos < 0 {
+		return -1
+	}
+	return p.order[p.pos]
+}
+
+// schedules the next track once the current one is about to end, called by the
+// mixer's Update on the game thread
+func (p *MusicPlayer) update() {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if p.current == nil || p.end < 0 || p.scheduled {
+		return
+	}
+
+	at := max(p.end-int64(p.mixer.seconds(p.cfg.Crossfade)), p.current.startAt)
+	if p.now()+int64(p.mixer.seconds(musicLookaheadSeconds)) < at {
+		return
+	}
+
+	pos, ok := p.nextPos()
+	if !ok {
+		// the last track plays out on its own
+		p.scheduled = true
+		return
+	}
+
+	if err := p.transition(at, pos); err != nil {
+		debug.EPrintf("Failed to play next track: %v", err)
+		p.scheduled = true
+	}
+}
+
+// starts the track at pos in the play order at mixer sample time at and fades
+// out the current track from there
+func (p *MusicPlayer) transition(at int64, pos int) error {
+	if pos < 0 || pos >= len(p.order) {
+		return debug.Errorf("Playlist is empty")
+	}
+
+	track := p.tracks[p.order[pos]]
+	src, length, err := p.open(track)
+	if err != nil {
+		return err
+	}
+
+	var v *Voice
+	if s, ok := src.(*streamSource); ok {
+		v = p.mixer.streamVoice(s, PlayConfig{Category: CategoryMusic})
+	} else {
+		v = newSourceVoice(p.mixer, src, PlayConfig{Category: CategoryMusic})
+	}
+
+	crossfade := p.mixer.seconds(p.cfg.Crossfade)
+	v.startAt = at
+
+	prev := p.current
+	if prev != nil {
+		v.fade = fade{start: at, length: crossfade, to: 1, curve: p.cfg.FadeIn}
+	}
+
+	a := p.mixer
+	a.commands.push(func() {
+		if prev != nil {
+			prev.fadeAt(at, 0, crossfade, p.cfg.FadeOut, true)
+		}
+		a.voices = append(a.voices, v)
+	})
+
+	p.current = v
+	p.pos = pos
+	p.scheduled = false
+	p.end = -1
+	if length >= 0 {
+		p.end = at + int64(length)
+	}
+
+	return nil
+}
+
+/*
+open prepares a track for a voice, streaming it if possible. It returns the
+length in output samples including every loop, or -1 if it loops forever.
+*/
+func (p *MusicPlayer) open(t MusicTrack) (source, int, error) {
+	if p.stream != nil {
+		s, err := p.stream(t.File)
+		if err == nil {
+			s.setLoop(t.Loops)
+			s.setLoopRegion(t.LoopStart, t.LoopEnd)
+
+			length := loopedLength(s.decoder.frames, t.LoopStart, t.LoopEnd, t.Loops)
+			if length >= 0 {
+				length = resampledLength(length, s.decoder.spec.Frequency, s.frequency)
+			}
+
+			return s, length, nil
+		}
+
+		// formats other than WAV can't be streamed yet
+		debug.WPrintf("Loading music fully into memory: %v", err)
+	}
+
+	a, err := p.load(t.File)
+	if err != nil {
+		return nil, 0, debug.ErrorWrapf(err, "Failed to load music %q", t.File)
+	}
+
+	from := a.Spec().Frequency
+	a = p.mixer.convert(a)
+	to := a.Spec().Frequency
+
+	// the loop points are in samples of the file
+	start := int(int64(t.LoopStart) * int64(to) / int64(from))
+	end := int(int64(t.LoopEnd) * int64(to) / int64(from))
+
+	s := newAssetSource(a)
+	s.setLoop(t.Loops)
+	s.setLoopRegion(start, end)
+
+	return s, loopedLength(a.DurationSamples(), start, end, t.Loops), nil
+}
+
+// returns the frames played by a source with the given loop region
+func loopedLength(frames, start, end, loops int) int {
+	if loops == LoopForever {
+		return -1
+	}
+
+	if end <= 0 || end > frames {
+		end = frames
+	}
+	if loops == 0 || end <= start {
+		return frames
+	}
+
+	return frames + loops*(end-start)
+}
+
+// returns the position after the current one in the play order
+func (p *MusicPlayer) nextPos() (int, bool) {
+	if len(p.order) == 0 {
+		return 0, false
+	}
+
+	if p.pos+1 < len(p.order) {
+		return p.pos + 1, true
+	}
+
+	if !p.cfg.Repeat {
+		return 0, false
+	}
+
+	last := -1
+	if p.pos >= 0 {
+		last = p.order[p.pos]
+	}
+	p.shuffle(last)
+
+	return 0, true
+}
+
+// rebuilds the play order, avoiding last as the first track so it does not repeat
+func (p *MusicPlayer) shuffle(last int) {
+	p.order = p.order[:0]
+	for i := range p.tracks {
+		p.order = append(p.order, i)
+	}
+
+	if !p.cfg.Shuffle {
+		return
+	}
+
+	p.rand.Shuffle(len(p.order), func(i, j int) {
+		p.order[i], p.order[j] = p.order[j], p.order[i]
+	})
+
+	if len(p.order) > 1 && p.order[0] == last {
+		p.order[0], p.order[1] = p.order[1], p.order[0]
+	}
+}
+
+// returns the mixer sample time of the next mixed sample
+func (p *MusicPlayer) now() int64 {
+	return p.mixer.published.clock.Load()
+}
@@ -0,0 +1,208 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mixer
+
+import (
+	"math/rand"
+	"testing"
+
+	"goarrg.com/asset/audio"
+	"goarrg.com/debug"
+)
+
+// returns a player loading tracks from memory instead of files
+func newTestMusicPlayer(a *audioMixer, tracks map[string]audio.Asset) *MusicPlayer {
+	p := newMusicPlayer(a)
+	p.stream = nil
+	p.rand = rand.New(rand.NewSource(1))
+	p.load = func(file string) (audio.Asset, error) {
+		if s, ok := tracks[file]; ok {
+			return s, nil
+		}
+		return nil, debug.Errorf("Unknown track %q", file)
+	}
+	return p
+}
+
+// mixes the given samples offline in small blocks, running Update in between
+// like the game thread would, and returns the left channel
+func render(a *audioMixer, samples int) []float32 {
+	var out []float32
+
+	for len(out) < samples {
+		a.Update()
+
+		n := min(64, samples-len(out))
+		a.mix(n)
+		out = append(out, a.masterTrack[audio.ChannelLeft][:n]...)
+	}
+
+	return out
+}
+
+// returns a mono asset where sample i is i/1024
+func rampAsset(samples int) audio.Asset {
+	track := make([]float32, samples)
+	for i := range track {
+		track[i] = float32(i) / 1024
+	}
+
+	return newTrackAsset(audio.Spec{Channels: audio.ChannelsMono(), Frequency: testFrequency},
+		audio.Track{audio.ChannelLeft: track})
+}
+
+func TestMusicCrossfade(t *testing.T) {
+	a := newTestMixer()
+	p := newTestMusicPlayer(a, map[string]audio.Asset{
+		"a": constantAsset(0.25, 2000),
+		"b": constantAsset(0.5, 2000),
+	})
+
+	// 441 samples
+	p.SetPlaylist(MusicConfig{Crossfade: 0.01}, MusicTrack{File: "a"}, MusicTrack{File: "b"})
+	if err := p.Play(); err != nil {
+		t.Fatal(err)
+	}
+
+	out := render(a, 4000)
+	const start = 2000 - 441
+
+	assertNear(t, "before crossfade", out[start-1], 0.25)
+	assertNear(t, "crossfade start", out[start], 0.25)
+	assertNear(t, "crossfade middle", out[start+220], 0.25*(1-220.0/441)+0.5*220.0/441)
+	assertNear(t, "crossfade end", out[2000], 0.5)
+	assertNear(t, "last sample", out[start+2000-1], 0.5)
+	assertNear(t, "after playlist", out[start+2000], 0)
+
+	if len(a.voices) != 0 {
+		t.Fatalf("%d voices still playing", len(a.voices))
+	}
+}
+
+func TestMusicIntroLoopGapless(t *testing.T) {
+	a := newTestMixer()
+	p := newTestMusicPlayer(a, map[string]audio.Asset{"ramp": rampAsset(100)})
+
+	p.SetPlaylist(MusicConfig{Repeat: true}, MusicTrack{File: "ramp", LoopStart: 20, LoopEnd: 50, Loops: 2})
+	if err := p.Play(); err != nil {
+		t.Fatal(err)
+	}
+
+	var want []float32
+	section := func(from, to int) {
+		for i := from; i < to; i++ {
+			want = append(want, float32(i)/1024)
+		}
+	}
+	for range 3 {
+		section(0, 50)
+		section(20, 50)
+		section(20, 50)
+		section(50, 100)
+	}
+
+	out := render(a, len(want))
+	for i := range want {
+		assertNear(t, "sample", out[i], want[i])
+	}
+}
+
+func TestMusicNext(t *testing.T) {
+	a := newTestMixer()
+	p := newTestMusicPlayer(a, map[string]audio.Asset{
+		"a": constantAsset(0.25, 10000),
+		"b": constantAsset(0.5, 10000),
+	})
+
+	p.SetPlaylist(MusicConfig{}, MusicTrack{File: "a", Loops: LoopForever}, MusicTrack{File: "b"})
+	if err := p.Play(); err != nil {
+		t.Fatal(err)
+	}
+
+	render(a, 100)
+	if err := p.Next(); err != nil {
+		t.Fatal(err)
+	}
+
+	out := render(a, 10)
+	assertNear(t, "after next", out[0], 0.5)
+
+	if p.Track() != 1 {
+		t.Fatalf("Playing track %d, want 1", p.Track())
+	}
+	if err := p.Next(); err == nil {
+		t.Fatal("Next past the last track without Repeat did not fail")
+	}
+
+	p.Stop(0)
+	out = render(a, 10)
+	assertNear(t, "after stop", out[0], 0)
+}
+
+func TestMusicShuffle(t *testing.T) {
+	a := newTestMixer()
+	p := newTestMusicPlayer(a, nil)
+
+	tracks := make([]MusicTrack, 5)
+	p.SetPlaylist(MusicConfig{Shuffle: true, Repeat: true}, tracks...)
+
+	last := -1
+	for round := 0; round < 50; round++ {
+		seen := make(map[int]bool)
+
+		for range tracks {
+			pos, ok := p.nextPos()
+			if !ok {
+				t.Fatal("Repeating playlist ended")
+			}
+			p.pos = pos
+
+			track := p.order[pos]
+			if track == last {
+				t.Fatalf("Track %d played twice in a row", track)
+			}
+			seen[track] = true
+			last = track
+		}
+
+		if len(seen) != len(tracks) {
+			t.Fatalf("Round %d played %d of %d tracks", round, len(seen), len(tracks))
+		}
+	}
+}
+
+func TestFadeCurves(t *testing.T) {
+	for _, curve := range []Curve{CurveLinear, CurveEqualPower, CurveSmooth} {
+		in := fade{length: 100, from: 0, to: 1, curve: curve}
+		out := fade{length: 100, from: 1, to: 0, curve: curve}
+
+		assertNear(t, "fade start", in.at(0), 0)
+		assertNear(t, "fade end", in.at(100), 1)
+
+		if curve != CurveEqualPower {
+			assertNear(t, "crossfade gain", in.at(50)+out.at(50), 1)
+			continue
+		}
+
+		for i := int64(0); i <= 100; i += 10 {
+			assertNear(t, "crossfade power", in.at(i)*in.at(i)+out.at(i)*out.at(i), 1)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mixer
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"slices"
+
+	"goarrg.com/asset/audio"
+	"goarrg.com/debug"
+)
+
+/*
+render mixes the next samples frames in blocks of the latency target, calling
+Update before every block like the game loop would. Streams are waited on
+instead of underrunning so the result only depends on what was played, it
+must not be used while an audio device pulls from the mixer.
+*/
+func (a *audioMixer) render(samples int) audio.Track {
+	a.mtx.Lock()
+	spec := a.spec
+	a.mtx.Unlock()
+
+	// render stands in for the audio thread so it can read its state
+	block := a.latency.target
+
+	out := make(audio.Track, len(spec.Channels))
+	for _, c := range spec.Channels {
+		out[c] = make([]float32, 0, samples)
+	}
+
+	for done := 0; done < samples; {
+		a.Update()
+
+		n := min(block, samples-done)
+
+		a.mtx.Lock()
+		streams := slices.Clone(a.streams)
+		a.mtx.Unlock()
+
+		for _, s := range streams {
+			s.wait(n)
+		}
+
+		a.mix(n)
+		for _, c := range spec.Channels {
+			out[c] = append(out[c], a.masterTrack[c][:n]...)
+		}
+
+		done += n
+	}
+
+	return out
+}
+
+/*
+RenderWAV mixes the next seconds of audio as fast as possible and writes them
+to w as a 32 bit float WAV file, for tests and capturing audio without a
+device. The mixer must have been initialized, with Init called directly and a
+nil platform if no goarrg instance runs, and must not be pulled from by an
+audio device at the same time. Unlike Mix no wall clock is involved, so the
+same calls produce the same samples on every run.
+*/
+func RenderWAV(w io.Writer, seconds float64) error {
+	spec := Mixer.outputSpec()
+
+	Mixer.mtx.Lock()
+	initialized := Mixer.spec.Frequency > 0
+	Mixer.mtx.Unlock()
+
+	if !initialized {
+		return debug.Errorf("Failed to render WAV, mixer not initialized")
+	}
+
+	samples := Mixer.seconds(seconds)
+	return writeWAV(w, spec, Mixer.render(samples), samples)
+}
+
+// same as RenderWAV but writes to the given file
+func RenderWAVFile(file string, seconds float64) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return debug.ErrorWrapf(err, "Failed to render WAV")
+	}
+
+	w := bufio.NewWriter(f)
+	if err := RenderWAV(w, seconds); err != nil {
+		f.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return debug.ErrorWrapf(err, "Failed to render WAV")
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,224 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mixer
+
+import (
+	"bytes"
+	"flag"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"goarrg.com/asset/audio"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden WAV files in testdata")
+
+// golden files are rendered at a low frequency to keep them small
+const goldenFrequency = 8000
+
+// returns a mixer with the default limiter as used by the game
+func newGoldenMixer() *audioMixer {
+	a := &audioMixer{}
+	a.init(audio.Spec{Channels: audio.ChannelsStereo(), Frequency: goldenFrequency})
+	return a
+}
+
+// returns a stereo asset of seconds of a sine at the golden frequency
+func goldenSine(amplitude float32, hz, seconds float64) audio.Asset {
+	spec := audio.Spec{Channels: audio.ChannelsStereo(), Frequency: goldenFrequency}
+	track := make(audio.Track)
+
+	for _, c := range spec.Channels {
+		track[c] = make([]float32, int(seconds*goldenFrequency))
+		for i := range track[c] {
+			track[c][i] = amplitude * float32(math.Sin(2*math.Pi*hz*float64(i)/goldenFrequency))
+		}
+	}
+
+	return newTrackAsset(spec, track)
+}
+
+// renders seconds of the mixer and compares them with testdata/name.wav
+func assertGolden(t *testing.T, a *audioMixer, name string, seconds float64) {
+	t.Helper()
+
+	samples := a.seconds(seconds)
+	got := new(bytes.Buffer)
+	if err := writeWAV(got, a.spec, a.render(samples), samples); err != nil {
+		t.Fatal(err)
+	}
+
+	file := filepath.Join("testdata", name+".wav")
+	if *updateGolden {
+		if err := os.MkdirAll("testdata", 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, got.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("%v, run the tests with -update to create it", err)
+	}
+
+	gotTrack, gotFrames := decodeWAV(t, got.Bytes())
+	wantTrack, wantFrames := decodeWAV(t, want)
+	if gotFrames != wantFrames {
+		t.Fatalf("Rendered %d frames, golden has %d", gotFrames, wantFrames)
+	}
+
+	// allow for rounding differences between platforms
+	for c := range wantTrack {
+		for i := range wantTrack[c] {
+			if math.Abs(float64(gotTrack[c][i]-wantTrack[c][i])) > 1e-4 {
+				t.Fatalf("%s: channel %d sample %d = %f, golden has %f", name, c, i, gotTrack[c][i], wantTrack[c][i])
+			}
+		}
+	}
+}
+
+func decodeWAV(t *testing.T, data []byte) ([][]float32, int) {
+	t.Helper()
+
+	d, err := newWAVDecoder(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	track := make([][]float32, len(d.spec.Channels))
+	for c := range track {
+		track[c] = make([]float32, d.frames)
+	}
+	if _, err := d.read(track); err != nil {
+		t.Fatal(err)
+	}
+
+	return track, d.frames
+}
+
+func TestGoldenScheduling(t *testing.T) {
+	a := newGoldenMixer()
+
+	a.play(goldenSine(0.3, 440, 1), PlayConfig{})
+	late := a.play(goldenSine(0.3, 660, 0.2), PlayConfig{Pan: -0.5})
+	fading := a.play(goldenSine(0.2, 220, 1), PlayConfig{Pan: 1})
+
+	late.startAt = int64(a.seconds(0.1))
+	fading.fadeAt(int64(a.seconds(0.15)), 0, a.seconds(0.2), CurveSmooth, true)
+
+	assertGolden(t, a, "scheduling", 0.5)
+
+	if len(a.voices) != 1 {
+		t.Fatalf("%d voices playing, want 1", len(a.voices))
+	}
+}
+
+func TestGoldenEffects(t *testing.T) {
+	a := newGoldenMixer()
+	sfx := a.categoryBuses[CategorySFX]
+
+	for _, e := range []Effect{NewBiquad(LowPass, 800, 2), NewDelay(0.05, 0.4, 0.3)} {
+		e.Init(a.spec)
+		sfx.effects = append(sfx.effects, e)
+	}
+
+	reverb := NewReverb(ReverbConfig{RoomSize: 0.6, Damping: 0.3, Wet: 0.5, Dry: 1, Width: 1})
+	reverb.Init(a.spec)
+	a.masterEffects = append(a.masterEffects, reverb)
+
+	a.play(goldenSine(0.4, 500, 0.1), PlayConfig{})
+	a.play(goldenSine(0.4, 1500, 0.05), PlayConfig{Category: CategoryUI, Pan: 0.7})
+
+	assertGolden(t, a, "effects", 0.5)
+}
+
+func TestGoldenClipping(t *testing.T) {
+	a := newGoldenMixer()
+
+	for _, hz := range []float64{300, 450, 700} {
+		a.play(goldenSine(0.8, hz, 0.3), PlayConfig{})
+	}
+
+	assertGolden(t, a, "clipping", 0.5)
+
+	if m := a.dynamics(); m.LimiterPeak <= 0 {
+		t.Fatal("Limiter did not reduce the gain")
+	}
+}
+
+func TestMixClock(t *testing.T) {
+	now := time.Unix(0, 0)
+	a := &audioMixer{now: func() time.Time { return now }, limiterConfig: LimiterConfig{Disabled: true}}
+	a.init(audio.Spec{Channels: audio.ChannelsStereo(), Frequency: goldenFrequency})
+	a.lastTime = now
+	a.play(goldenSine(0.5, 440, 1), PlayConfig{})
+
+	// the first call fills the buffer, later ones replace what was played and
+	// the underrun after a second grows the buffer by a step
+	for _, step := range []struct {
+		elapsed time.Duration
+		samples int
+	}{{0, 800}, {50 * time.Millisecond, 400}, {0, 0}, {10 * time.Millisecond, 80}, {time.Second, 880}} {
+		now = now.Add(step.elapsed)
+		if n, _ := a.Mix(); n != step.samples {
+			t.Fatalf("Mixed %d samples after %v, want %d", n, step.elapsed, step.samples)
+		}
+	}
+
+	if a.clock != 800+400+80+880 {
+		t.Fatalf("Clock at %d", a.clock)
+	}
+}
+
+func TestWriteWAV(t *testing.T) {
+	for _, channels := range [][]audio.Channel{audio.ChannelsMono(), audio.ChannelsStereo(), audio.Channels5Point1(), audio.Channels7Point1()} {
+		spec := audio.Spec{Channels: channels, Frequency: 22050}
+		track := make(audio.Track)
+		for i, c := range channels {
+			track[c] = []float32{float32(i) / 8, -0.5, 1.5}
+		}
+
+		buf := new(bytes.Buffer)
+		if err := writeWAV(buf, spec, track, 3); err != nil {
+			t.Fatal(err)
+		}
+
+		d, err := newWAVDecoder(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d.spec.Frequency != spec.Frequency || len(d.spec.Channels) != len(channels) || d.frames != 3 {
+			t.Fatalf("Decoded %+v with %d frames", d.spec, d.frames)
+		}
+
+		got, _ := decodeWAV(t, buf.Bytes())
+		for i, c := range channels {
+			for j := range got[i] {
+				assertNear(t, "sample", got[i][j], track[c][j])
+			}
+		}
+	}
+}
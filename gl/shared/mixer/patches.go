@@ -0,0 +1,92 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mixer
+
+// starting points for common retro effects, tweak the fields to taste
+
+// a pickup jumping up a fifth
+func CoinPatch() Patch {
+	return Patch{
+		Wave:          WaveSquare,
+		Frequency:     988,
+		Arpeggio:      1.5,
+		ArpeggioDelay: 0.06,
+		Envelope:      Envelope{Hold: 0.06, Release: 0.25},
+		Gain:          0.4,
+	}
+}
+
+// a falling zap
+func LaserPatch() Patch {
+	return Patch{
+		Wave:      WaveSaw,
+		Frequency: 1400,
+		Slide:     -6,
+		Envelope:  Envelope{Hold: 0.05, Release: 0.15},
+		Gain:      0.4,
+	}
+}
+
+// low noise rumbling down
+func ExplosionPatch() Patch {
+	return Patch{
+		Wave:      WaveNoise,
+		Frequency: 120,
+		Slide:     -1.5,
+		Envelope:  Envelope{Attack: 0.005, Hold: 0.1, Release: 0.6},
+		Gain:      0.8,
+	}
+}
+
+// a square sliding up
+func JumpPatch() Patch {
+	return Patch{
+		Wave:      WaveSquare,
+		Frequency: 300,
+		Slide:     4,
+		Duty:      0.25,
+		DutySweep: 1,
+		Envelope:  Envelope{Hold: 0.08, Release: 0.12},
+		Gain:      0.4,
+	}
+}
+
+// a short bell-like FM tick for UI
+func BlipPatch() Patch {
+	return Patch{
+		Wave:      WaveSine,
+		Frequency: 1200,
+		FMRatio:   3.5,
+		FMIndex:   2,
+		Envelope:  Envelope{Attack: 0.002, Decay: 0.05, Sustain: 0.3, Release: 0.05},
+		Gain:      0.5,
+	}
+}
+
+// a quick noise burst with a falling body
+func HitPatch() Patch {
+	return Patch{
+		Wave:      WaveNoise,
+		Frequency: 800,
+		Slide:     -8,
+		Envelope:  Envelope{Hold: 0.02, Release: 0.15},
+		Gain:      0.6,
+	}
+}
@@ -0,0 +1,98 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mixer
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// slots of the command ring, commands beyond it wait in the backlog
+const commandQueueSize = 1024
+
+// command changes the audio thread's state, it runs at the start of the next mixed block
+type command func()
+
+/*
+commandQueue passes commands from the game thread to the audio thread without
+the audio thread ever taking a lock. It is a single producer single consumer
+ring, producers are serialized by mtx which the consumer never touches.
+Commands that do not fit into the ring are kept in a backlog that later pushes
+and flush move over, so the producer never waits on the audio thread either.
+*/
+type commandQueue struct {
+	ring [commandQueueSize]command
+	// positions only grow, head is the next command to run and tail the next
+	// free slot, both are taken modulo the ring size
+	head atomic.Uint64
+	tail atomic.Uint64
+
+	mtx     sync.Mutex
+	backlog []command
+}
+
+// queues a command, it is safe to call from any goroutine but the audio thread
+func (q *commandQueue) push(c command) {
+	q.mtx.Lock()
+	q.backlog = append(q.backlog, c)
+	q.flushLocked()
+	q.mtx.Unlock()
+}
+
+// moves what fits of the backlog into the ring
+func (q *commandQueue) flush() {
+	q.mtx.Lock()
+	q.flushLocked()
+	q.mtx.Unlock()
+}
+
+// must be called with mtx held
+func (q *commandQueue) flushLocked() {
+	tail := q.tail.Load()
+	free := uint64(len(q.ring)) - (tail - q.head.Load())
+	n := min(uint64(len(q.backlog)), free)
+	if n == 0 {
+		return
+	}
+
+	for i := range n {
+		q.ring[(tail+i)%commandQueueSize] = q.backlog[i]
+	}
+	q.tail.Store(tail + n)
+
+	left := copy(q.backlog, q.backlog[n:])
+	// drop the references so finished commands can be collected
+	clear(q.backlog[left:])
+	q.backlog = q.backlog[:left]
+}
+
+// runs every command in the ring in the order they were pushed, only called by the audio thread
+func (q *commandQueue) drain() {
+	head, tail := q.head.Load(), q.tail.Load()
+
+	for ; head != tail; head++ {
+		slot := &q.ring[head%commandQueueSize]
+		c := *slot
+		*slot = nil
+		c()
+	}
+
+	q.head.Store(head)
+}
@@ -0,0 +1,211 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mixer
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"goarrg.com/gmath"
+)
+
+func TestCommandQueue(t *testing.T) {
+	var q commandQueue
+	var ran []int
+
+	for i := range commandQueueSize + 10 {
+		q.push(func() { ran = append(ran, i) })
+	}
+
+	// the overflow waits in the backlog until there is room again
+	q.drain()
+	if len(ran) != commandQueueSize {
+		t.Fatalf("Ran %d commands, want %d", len(ran), commandQueueSize)
+	}
+
+	q.flush()
+	q.drain()
+	if len(ran) != commandQueueSize+10 {
+		t.Fatalf("Ran %d commands after flush, want %d", len(ran), commandQueueSize+10)
+	}
+
+	for i, n := range ran {
+		if i != n {
+			t.Fatalf("Command %d ran as %d", n, i)
+		}
+	}
+}
+
+func TestCommandQueueStress(t *testing.T) {
+	const (
+		producers = 4
+		commands  = 20000
+	)
+
+	var q commandQueue
+	var last [producers]int
+	var ran atomic.Int64
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for ran.Load() < producers*commands {
+			q.drain()
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for p := range producers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 1; i <= commands; i++ {
+				q.push(func() {
+					if last[p] != i-1 {
+						t.Errorf("Producer %d command %d ran after %d", p, i, last[p])
+					}
+					last[p] = i
+					ran.Add(1)
+				})
+			}
+		}()
+	}
+	wg.Wait()
+
+	// like Update, hands over what was left in the backlog
+	for {
+		select {
+		case <-done:
+			return
+		default:
+			q.flush()
+		}
+	}
+}
+
+func TestMixerStress(t *testing.T) {
+	a := newTestMixer()
+	a.maxVoices = 16
+	sfx := a.categoryBuses[CategorySFX]
+
+	stop := make(chan struct{})
+	mixed := make(chan struct{})
+	go func() {
+		defer close(mixed)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				a.mix(a.bufferSamples / 10)
+			}
+		}
+	}()
+
+	asset := constantAsset(0.01, testFrequency/10)
+	for i := range 2000 {
+		v := a.play(asset, PlayConfig{Spatial: &SpatialConfig{}})
+		v.SetGain(0.5)
+		v.SetPitch(1.5)
+		v.SetPosition(gmath.Point3f64{X: float64(i)}, gmath.Vector3f64{})
+		v.FadeOut(0.01, CurveLinear)
+		sfx.SetGain(float32(i%2) + 0.5)
+
+		if i%3 == 0 {
+			v.Stop()
+		}
+		if stats := a.voiceStats(); stats.Playing > 16 {
+			t.Fatalf("Stats %+v", stats)
+		}
+		a.Update()
+	}
+
+	close(stop)
+	<-mixed
+}
+
+func BenchmarkCommandQueue(b *testing.B) {
+	var q commandQueue
+	n := 0
+
+	for i := 0; i < b.N; i++ {
+		q.push(func() { n++ })
+		if i%64 == 63 {
+			q.drain()
+		}
+	}
+	q.drain()
+}
+
+// measures how long the game thread takes to start a voice while the audio thread mixes
+func BenchmarkPlayWhileMixing(b *testing.B) {
+	a := newTestMixer()
+	a.maxVoices = 32
+	asset := constantAsset(0.01, 64)
+
+	stop := make(chan struct{})
+	mixed := make(chan struct{})
+	go func() {
+		defer close(mixed)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				a.mix(a.bufferSamples)
+			}
+		}
+	}()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a.play(asset, PlayConfig{})
+		if i%64 == 63 {
+			a.Update()
+		}
+	}
+	b.StopTimer()
+
+	close(stop)
+	<-mixed
+}
+
+// measures a block with a frame's worth of parameter changes to apply
+func BenchmarkMixCommands(b *testing.B) {
+	a := newTestMixer()
+	a.maxVoices = 0
+
+	var voices []*Voice
+	for range 32 {
+		v := a.play(constantAsset(0.01, 64), PlayConfig{})
+		v.SetLoop(LoopForever)
+		voices = append(voices, v)
+	}
+	a.mix(a.bufferSamples)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, v := range voices {
+			v.SetGain(float32(i%2) + 0.5)
+		}
+		a.mix(a.bufferSamples)
+	}
+}
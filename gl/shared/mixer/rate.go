@@ -0,0 +1,162 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mixer
+
+import (
+	"goarrg.com/asset/audio"
+)
+
+// frames read from the wrapped source at a time
+const rateChunkFrames = 256
+
+// the playback rate is clamped to this range so the source is never read backwards or too fast
+const (
+	minRate = 0.125
+	maxRate = 8
+)
+
+/*
+rateSource plays another source faster or slower with linear interpolation,
+changing the pitch with the speed. Rate changes are ramped per frame so they
+glide instead of stepping.
+*/
+type rateSource struct {
+	src  source
+	rate ramp
+	// frames read from src not consumed yet, pos is relative to the first
+	in  [][]float32
+	pos float64
+	buf [][]float32
+	// src returned fewer frames than asked
+	drained bool
+}
+
+func newRateSource(src source) *rateSource {
+	channels := len(src.channels())
+	s := &rateSource{
+		src:  src,
+		rate: newRamp(1),
+		in:   make([][]float32, channels),
+		buf:  make([][]float32, channels),
+	}
+
+	for c := range s.buf {
+		s.buf[c] = make([]float32, rateChunkFrames)
+	}
+
+	return s
+}
+
+func (s *rateSource) channels() []audio.Channel {
+	return s.src.channels()
+}
+
+// ramps to the rate over the given frames of output
+func (s *rateSource) setRate(rate float32, frames int) {
+	rate = min(max(rate, minRate), maxRate)
+	if rate != s.rate.target {
+		s.rate.set(rate, frames)
+	}
+}
+
+// reads the next chunk of src into in, returns false once src ran out
+func (s *rateSource) fill() bool {
+	if s.drained {
+		return false
+	}
+
+	n := s.src.read(s.buf)
+	for c := range s.in {
+		s.in[c] = append(s.in[c], s.buf[c][:n]...)
+	}
+
+	s.drained = n < rateChunkFrames
+	return n > 0
+}
+
+func (s *rateSource) read(dst [][]float32) int {
+	// nothing buffered and nothing to interpolate, read straight through
+	if s.rate.value == 1 && s.rate.remaining == 0 && s.pos == 0 && len(s.in[0]) == 0 {
+		n := s.src.read(dst)
+		s.drained = n < len(dst[0])
+		return n
+	}
+
+	n := 0
+	for ; n < len(dst[0]); n++ {
+		i := int(s.pos)
+		for i+1 >= len(s.in[0]) {
+			if !s.fill() {
+				break
+			}
+		}
+		if i >= len(s.in[0]) {
+			break
+		}
+
+		f := float32(s.pos - float64(i))
+		for c, in := range s.in {
+			a := in[i]
+			b := a
+			if i+1 < len(in) {
+				b = in[i+1]
+			}
+			dst[c][n] = a + (b-a)*f
+		}
+
+		s.pos += float64(s.rate.next())
+	}
+
+	// drop the frames behind the read position
+	if drop := min(int(s.pos), len(s.in[0])); drop > 0 {
+		for c := range s.in {
+			s.in[c] = append(s.in[c][:0], s.in[c][drop:]...)
+		}
+		s.pos -= float64(drop)
+	}
+
+	return n
+}
+
+func (s *rateSource) ended() bool {
+	return int(s.pos) >= len(s.in[0]) && s.src.ended()
+}
+
+func (s *rateSource) reset() {
+	for c := range s.in {
+		s.in[c] = s.in[c][:0]
+	}
+	s.pos = 0
+	s.drained = false
+}
+
+func (s *rateSource) seek(frame int) {
+	s.src.seek(frame)
+	s.reset()
+}
+
+func (s *rateSource) setLoop(count int) {
+	s.src.setLoop(count)
+	s.drained = false
+}
+
+func (s *rateSource) setLoopRegion(start, end int) {
+	s.src.setLoopRegion(start, end)
+}
@@ -0,0 +1,119 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mixer
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"goarrg.com/asset/audio"
+)
+
+func TestRateSource(t *testing.T) {
+	for _, rate := range []float32{1, 2, 0.5, 1.37} {
+		s := newRateSource(newAssetSource(rampAsset(1000)))
+		s.setRate(rate, 0)
+
+		// read in uneven blocks, the result must not depend on them
+		var out []float32
+		for _, n := range []int{1, 7, 300, 64, 1000, 2000} {
+			block := [][]float32{make([]float32, n)}
+			out = append(out, block[0][:s.read(block)]...)
+		}
+
+		want := int(math.Ceil(1000 / float64(rate)))
+		if len(out) != want || !s.ended() {
+			t.Fatalf("Rate %f played %d frames, want %d", rate, len(out), want)
+		}
+
+		for i, v := range out {
+			x := float64(i) * float64(rate)
+			// the last frame holds as nothing follows it
+			assertNear(t, "rate", v, float32(min(x, 999))/1024)
+		}
+	}
+}
+
+func TestRateGlide(t *testing.T) {
+	s := newRateSource(newAssetSource(rampAsset(10000)))
+	s.setRate(2, 1000)
+
+	block := [][]float32{make([]float32, 2000)}
+	s.read(block)
+	out := block[0]
+
+	// the step between frames grows steadily from 1 to 2 frames of the ramp
+	last := float32(1) / 1024
+	for i := 1; i < len(out); i++ {
+		step := out[i] - out[i-1]
+		if step < last-1e-6 || step > 2.0/1024+1e-6 {
+			t.Fatalf("Step %f at frame %d after %f", step*1024, i, last*1024)
+		}
+		last = step
+	}
+	assertNear(t, "final rate", last*1024, 2)
+}
+
+func TestVoicePitch(t *testing.T) {
+	a := newTestMixer()
+	a.play(rampAsset(10000), PlayConfig{Pitch: 2})
+	a.mix(1000)
+	fast := append([]float32(nil), a.masterTrack[audio.ChannelLeft][:1000]...)
+
+	a = newTestMixer()
+	v := a.play(rampAsset(10000), PlayConfig{})
+	a.mix(2000)
+	normal := a.masterTrack[audio.ChannelLeft]
+
+	for i := range fast {
+		assertNear(t, "pitch", fast[i], normal[2*i])
+	}
+
+	if _, ok := v.src.(*rateSource); ok {
+		t.Fatal("Voice at its native rate was wrapped in a rateSource")
+	}
+
+	// changing the pitch later glides from the current position
+	v.SetPitch(0.5)
+	a.mix(a.rampSamples + 100)
+	out := a.masterTrack[audio.ChannelLeft]
+	assertNear(t, "continues", out[0]-normal[1999], normal[1]-normal[0])
+	assertNear(t, "glided", out[a.rampSamples+99]-out[a.rampSamples+98], (normal[1]-normal[0])/2)
+}
+
+func TestPitchVariance(t *testing.T) {
+	a := newTestMixer()
+	a.rand = rand.New(rand.NewSource(1))
+
+	seen := make(map[float32]bool)
+	for i := 0; i < 100; i++ {
+		v := newVoice(a, rampAsset(100), PlayConfig{Pitch: 1.5, PitchVariance: 2})
+
+		if v.pitch < 1.5*semitones(-2) || v.pitch > 1.5*semitones(2) {
+			t.Fatalf("Pitch %f outside of 2 semitones around 1.5", v.pitch)
+		}
+		seen[v.pitch] = true
+	}
+
+	if len(seen) < 90 {
+		t.Fatalf("Only %d different pitches", len(seen))
+	}
+}
@@ -0,0 +1,260 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mixer
+
+import (
+	"math"
+	"sync"
+
+	"goarrg.com/asset/audio"
+)
+
+type ResampleQuality uint8
+
+const (
+	// windowed sinc, slower but does not alias
+	ResampleSinc ResampleQuality = iota
+	// linear interpolation, fast but aliases and dulls high frequencies
+	ResampleLinear
+)
+
+const (
+	// zero crossings on each side of the sinc kernel
+	sincZeroCrossings = 16
+	// kernel table entries per zero crossing, values in between are interpolated
+	sincResolution = 256
+)
+
+var (
+	sincTableOnce sync.Once
+	sincTable     []float32
+)
+
+/*
+Resample converts every channel of the asset to the given frequency, the
+asset is returned as is if it already matches.
+*/
+func Resample(s audio.Asset, frequency int, quality ResampleQuality) audio.Asset {
+	spec := s.Spec()
+	if spec.Frequency == frequency || frequency <= 0 {
+		return s
+	}
+
+	track := make(audio.Track, len(spec.Channels))
+	for _, c := range spec.Channels {
+		switch quality {
+		case ResampleLinear:
+			track[c] = resampleLinear(s.Track()[c], spec.Frequency, frequency)
+		default:
+			track[c] = resampleSinc(s.Track()[c], spec.Frequency, frequency)
+		}
+	}
+
+	spec.Frequency = frequency
+	return newTrackAsset(spec, track)
+}
+
+func resampledLength(samples, from, to int) int {
+	return int((int64(samples)*int64(to) + int64(from) - 1) / int64(from))
+}
+
+func resampleLinear(in []float32, from, to int) []float32 {
+	out := make([]float32, resampledLength(len(in), from, to))
+	step := float64(from) / float64(to)
+
+	for j := range out {
+		x := float64(j) * step
+		i := int(x)
+		f := float32(x - float64(i))
+
+		a := in[i]
+		b := a
+		if i+1 < len(in) {
+			b = in[i+1]
+		}
+
+		out[j] = a + (b-a)*f
+	}
+
+	return out
+}
+
+/*
+resampleSinc evaluates a Blackman windowed sinc at every output position, when
+downsampling the kernel is stretched to move its cutoff below the new Nyquist.
+*/
+func resampleSinc(in []float32, from, to int) []float32 {
+	sincTableOnce.Do(initSincTable)
+
+	out := make([]float32, resampledLength(len(in), from, to))
+	step := float64(from) / float64(to)
+	cutoff := min(1, 1/step)
+	width := float64(sincZeroCrossings) / cutoff
+
+	for j := range out {
+		x := float64(j) * step
+		first := max(int(math.Floor(x-width))+1, 0)
+		last := min(int(math.Floor(x+width)), len(in)-1)
+
+		sum := float32(0)
+		for i := first; i <= last; i++ {
+			sum += in[i] * sincKernel(math.Abs(x-float64(i))*cutoff)
+		}
+
+		out[j] = sum * float32(cutoff)
+	}
+
+	return out
+}
+
+// looks up the windowed sinc at t zero crossings from the center
+func sincKernel(t float64) float32 {
+	pos := t * sincResolution
+	i := int(pos)
+	if i >= len(sincTable)-1 {
+		return 0
+	}
+
+	f := float32(pos - float64(i))
+	return sincTable[i] + (sincTable[i+1]-sincTable[i])*f
+}
+
+func initSincTable() {
+	sincTable = make([]float32, sincZeroCrossings*sincResolution+1)
+
+	for i := range sincTable {
+		t := float64(i) / sincResolution
+		sinc := 1.0
+		if t > 0 {
+			sinc = math.Sin(math.Pi*t) / (math.Pi * t)
+		}
+
+		w := t / sincZeroCrossings
+		window := 0.42 + 0.5*math.Cos(math.Pi*w) + 0.08*math.Cos(2*math.Pi*w)
+		sincTable[i] = float32(sinc * window)
+	}
+}
+
+/*
+streamResampler converts a stream fed in chunks with the same math as
+Resample, it keeps enough input around each output position so the result
+does not depend on how the input was chunked.
+*/
+type streamResampler struct {
+	quality ResampleQuality
+	step    float64
+	cutoff  float64
+	width   float64
+	in      [][]float32
+	// position of the next output frame in in
+	pos float64
+}
+
+func newStreamResampler(channels, from, to int, quality ResampleQuality) *streamResampler {
+	sincTableOnce.Do(initSincTable)
+
+	r := &streamResampler{
+		quality: quality,
+		step:    float64(from) / float64(to),
+		in:      make([][]float32, channels),
+	}
+
+	r.cutoff = min(1, 1/r.step)
+	r.width = float64(sincZeroCrossings) / r.cutoff
+	if quality == ResampleLinear {
+		r.width = 1
+	}
+
+	return r
+}
+
+// queues n frames of src as the input following what was written before
+func (r *streamResampler) write(src [][]float32, n int) {
+	for c := range r.in {
+		r.in[c] = append(r.in[c], src[c][:n]...)
+	}
+}
+
+/*
+read fills dst with as many frames as the queued input allows and returns how
+many were written. With flush set the input is treated as ended so the last
+frames are computed as if silence followed.
+*/
+func (r *streamResampler) read(dst [][]float32, flush bool) int {
+	queued := len(r.in[0])
+	n := 0
+
+	for ; n < len(dst[0]); n++ {
+		x := r.pos
+		if flush {
+			if x >= float64(queued) {
+				break
+			}
+		} else if int(math.Floor(x+r.width)) >= queued {
+			break
+		}
+
+		for c, in := range r.in {
+			if r.quality == ResampleLinear {
+				i := int(x)
+				f := float32(x - float64(i))
+
+				a := in[i]
+				b := a
+				if i+1 < len(in) {
+					b = in[i+1]
+				}
+
+				dst[c][n] = a + (b-a)*f
+				continue
+			}
+
+			first := max(int(math.Floor(x-r.width))+1, 0)
+			last := min(int(math.Floor(x+r.width)), len(in)-1)
+
+			sum := float32(0)
+			for i := first; i <= last; i++ {
+				sum += in[i] * sincKernel(math.Abs(x-float64(i))*r.cutoff)
+			}
+
+			dst[c][n] = sum * float32(r.cutoff)
+		}
+
+		r.pos += r.step
+	}
+
+	// drop the input no future output position can reach
+	if drop := min(max(int(math.Floor(r.pos-r.width)), 0), queued); drop > 0 {
+		for c := range r.in {
+			r.in[c] = append(r.in[c][:0], r.in[c][drop:]...)
+		}
+		r.pos -= float64(drop)
+	}
+
+	return n
+}
+
+// discards every queued frame, used when the input jumps to another position
+func (r *streamResampler) reset() {
+	for c := range r.in {
+		r.in[c] = r.in[c][:0]
+	}
+	r.pos = 0
+}
@@ -0,0 +1,102 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mixer
+
+import (
+	"math"
+	"testing"
+
+	"goarrg.com/asset/audio"
+)
+
+func sineAsset(hz float64, frequency, samples int) audio.Asset {
+	spec := audio.Spec{Channels: audio.ChannelsMono(), Frequency: frequency}
+	track := audio.Track{audio.ChannelLeft: make([]float32, samples)}
+
+	for i := range track[audio.ChannelLeft] {
+		track[audio.ChannelLeft][i] = float32(math.Sin(2 * math.Pi * hz * float64(i) / float64(frequency)))
+	}
+
+	return newTrackAsset(spec, track)
+}
+
+// estimates the frequency from the first and last rising zero crossing
+func measureFrequency(samples []float32, frequency int) float64 {
+	first, last := -1.0, -1.0
+	crossings := 0
+
+	for i := 1; i < len(samples); i++ {
+		if samples[i-1] < 0 && samples[i] >= 0 {
+			pos := float64(i-1) + float64(-samples[i-1]/(samples[i]-samples[i-1]))
+			if first < 0 {
+				first = pos
+			}
+			last = pos
+			crossings++
+		}
+	}
+
+	return float64(crossings-1) * float64(frequency) / (last - first)
+}
+
+func rms(samples []float32) float64 {
+	sum := 0.0
+	for _, s := range samples {
+		sum += float64(s) * float64(s)
+	}
+	return math.Sqrt(sum / float64(len(samples)))
+}
+
+func TestResampleFrequency(t *testing.T) {
+	const hz = 1000
+
+	for _, quality := range []ResampleQuality{ResampleSinc, ResampleLinear} {
+		for _, rates := range [][2]int{{48000, 44100}, {22050, 44100}, {44100, 48000}, {44100, 44100}} {
+			s := Resample(sineAsset(hz, rates[0], rates[0]), rates[1], quality)
+
+			if s.Spec().Frequency != rates[1] {
+				t.Fatalf("Resampled to %d Hz, want %d Hz", s.Spec().Frequency, rates[1])
+			}
+			if s.DurationSamples() != rates[1] {
+				t.Fatalf("Resampled 1s to %d samples, want %d", s.DurationSamples(), rates[1])
+			}
+
+			// skip the edges where the kernel runs out of input
+			body := s.Track()[audio.ChannelLeft][100 : s.DurationSamples()-100]
+
+			if got := measureFrequency(body, rates[1]); math.Abs(got-hz) > 0.1 {
+				t.Fatalf("Quality %d %v: sine measured at %f Hz, want %d Hz", quality, rates, got, hz)
+			}
+			if got := rms(body); math.Abs(got-math.Sqrt2/2) > 0.01 {
+				t.Fatalf("Quality %d %v: sine rms %f, want %f", quality, rates, got, math.Sqrt2/2)
+			}
+		}
+	}
+}
+
+func TestResampleSincAntiAlias(t *testing.T) {
+	// above the Nyquist of the output so it would alias to 7050 Hz
+	s := Resample(sineAsset(15000, 48000, 48000), 22050, ResampleSinc)
+	body := s.Track()[audio.ChannelLeft][100 : s.DurationSamples()-100]
+
+	if got := rms(body); got > 0.01 {
+		t.Fatalf("Aliased tone rms %f, want < 0.01", got)
+	}
+}
@@ -0,0 +1,186 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mixer
+
+import (
+	"goarrg.com/asset/audio"
+)
+
+// Freeverb's tuning, the delay lengths are in samples at 44.1kHz
+var (
+	reverbCombTuning    = [...]int{1116, 1188, 1277, 1356, 1422, 1491, 1557, 1617}
+	reverbAllpassTuning = [...]int{556, 441, 341, 225}
+)
+
+const (
+	reverbStereoSpread = 23
+	reverbFixedGain    = 0.015
+	reverbScaleDamp    = 0.4
+	reverbScaleRoom    = 0.28
+	reverbOffsetRoom   = 0.7
+	reverbAllpassGain  = 0.5
+)
+
+type ReverbConfig struct {
+	// 0 to 1, larger rooms ring longer
+	RoomSize float32
+	// 0 to 1, how fast high frequencies die out
+	Damping float32
+	Wet     float32
+	Dry     float32
+	// 0 to 1, stereo width of the wet signal
+	Width float32
+}
+
+/*
+Reverb is a Freeverb style reverb, 8 parallel lowpass feedback combs followed
+by 4 serial allpasses for each of the left and right channel. The input is
+summed to mono, other channels than left and right are passed through dry.
+*/
+type Reverb struct {
+	roomSize param
+	damping  param
+	wet      param
+	dry      param
+	width    param
+
+	left, right reverbChannel
+	hasRight    bool
+}
+
+type reverbChannel struct {
+	combs     [len(reverbCombTuning)]reverbComb
+	allpasses [len(reverbAllpassTuning)]reverbAllpass
+}
+
+type reverbComb struct {
+	buf    []float32
+	pos    int
+	filter float32
+}
+
+type reverbAllpass struct {
+	buf []float32
+	pos int
+}
+
+func NewReverb(cfg ReverbConfig) *Reverb {
+	r := &Reverb{}
+	r.roomSize.init(cfg.RoomSize)
+	r.damping.init(cfg.Damping)
+	r.wet.init(cfg.Wet)
+	r.dry.init(cfg.Dry)
+	r.width.init(cfg.Width)
+	return r
+}
+
+// changes the parameters, the change is smoothed
+func (r *Reverb) Set(cfg ReverbConfig) {
+	r.roomSize.set(cfg.RoomSize)
+	r.damping.set(cfg.Damping)
+	r.wet.set(cfg.Wet)
+	r.dry.set(cfg.Dry)
+	r.width.set(cfg.Width)
+}
+
+func (r *Reverb) Init(spec audio.Spec) {
+	scale := float64(spec.Frequency) / 44100
+	r.left.init(scale, 0)
+	r.right.init(scale, reverbStereoSpread)
+	r.hasRight = false
+	for _, c := range spec.Channels {
+		r.hasRight = r.hasRight || c == audio.ChannelRight
+	}
+
+	samples := automationSamples(spec.Frequency)
+	for _, p := range []*param{&r.roomSize, &r.damping, &r.wet, &r.dry, &r.width} {
+		p.samples = samples
+		p.ramp = newRamp(p.target())
+	}
+}
+
+func (r *Reverb) Process(track audio.Track, samples int) {
+	for _, p := range []*param{&r.roomSize, &r.damping, &r.wet, &r.dry, &r.width} {
+		p.update()
+	}
+
+	left := track[audio.ChannelLeft]
+	right := track[audio.ChannelRight]
+
+	for i := 0; i < samples; i++ {
+		feedback := r.roomSize.next()*reverbScaleRoom + reverbOffsetRoom
+		damp := r.damping.next() * reverbScaleDamp
+		wet := r.wet.next()
+		dry := r.dry.next()
+		width := r.width.next()
+
+		in := left[i]
+		if r.hasRight {
+			in += right[i]
+		}
+		in *= reverbFixedGain
+
+		outL := r.left.process(in, feedback, damp)
+		if !r.hasRight {
+			left[i] = outL*wet + left[i]*dry
+			continue
+		}
+		outR := r.right.process(in, feedback, damp)
+
+		wet1 := wet * (width/2 + 0.5)
+		wet2 := wet * ((1 - width) / 2)
+		left[i], right[i] = outL*wet1+outR*wet2+left[i]*dry, outR*wet1+outL*wet2+right[i]*dry
+	}
+}
+
+func (c *reverbChannel) init(scale float64, spread int) {
+	for i, n := range reverbCombTuning {
+		c.combs[i] = reverbComb{buf: make([]float32, max(int(float64(n+spread)*scale), 1))}
+	}
+	for i, n := range reverbAllpassTuning {
+		c.allpasses[i] = reverbAllpass{buf: make([]float32, max(int(float64(n+spread)*scale), 1))}
+	}
+}
+
+func (c *reverbChannel) process(in, feedback, damp float32) float32 {
+	out := float32(0)
+	for i := range c.combs {
+		out += c.combs[i].process(in, feedback, damp)
+	}
+	for i := range c.allpasses {
+		out = c.allpasses[i].process(out)
+	}
+	return out
+}
+
+func (c *reverbComb) process(in, feedback, damp float32) float32 {
+	out := c.buf[c.pos]
+	c.filter = out*(1-damp) + c.filter*damp
+	c.buf[c.pos] = in + c.filter*feedback
+	c.pos = (c.pos + 1) % len(c.buf)
+	return out
+}
+
+func (a *reverbAllpass) process(in float32) float32 {
+	delayed := a.buf[a.pos]
+	a.buf[a.pos] = in + delayed*reverbAllpassGain
+	a.pos = (a.pos + 1) % len(a.buf)
+	return delayed - in
+}
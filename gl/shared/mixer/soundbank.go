@@ -0,0 +1,206 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mixer
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"goarrg.com/asset/audio"
+	"goarrg.com/debug"
+)
+
+/*
+SoundBank caches decoded sounds by file name so voices playing the same sound
+share one audio.Asset. Entries are reference counted by Load/Unload and by the
+voices playing them, unreferenced entries stay cached until Trim is called.
+*/
+type SoundBank struct {
+	mtx    sync.Mutex
+	sounds map[string]*bankEntry
+	mixer  *audioMixer
+	load   func(string) (audio.Asset, error)
+}
+
+type bankEntry struct {
+	// closed once asset or err is set, the first user loads the sound outside
+	// the bank's mtx while later users wait on it
+	ready chan struct{}
+	asset audio.Asset
+	err   error
+	// only incremented with the bank's mtx held so Trim can't race a new user
+	refs atomic.Int64
+	// references taken by Load, guarded by the bank's mtx
+	pins int
+}
+
+// returns an entry for an asset that needs no loading, pinned as if by Load
+func newPinnedEntry(asset audio.Asset) *bankEntry {
+	e := &bankEntry{ready: make(chan struct{}), asset: asset, pins: 1}
+	e.refs.Store(1)
+	close(e.ready)
+	return e
+}
+
+func (e *bankEntry) loaded() bool {
+	select {
+	case <-e.ready:
+		return e.err == nil
+	default:
+		return false
+	}
+}
+
+// used by PlaySound and PlaySoundWithConfig
+var DefaultSoundBank = NewSoundBank()
+
+func NewSoundBank() *SoundBank {
+	return &SoundBank{
+		sounds: make(map[string]*bankEntry),
+		mixer:  Mixer,
+		load:   audio.Load,
+	}
+}
+
+/*
+returns the entry for the sound with a reference taken, loading it if needed.
+Decoding and resampling happen without the bank's mtx held so other sounds are
+not blocked, users of a sound that is still loading wait for it.
+*/
+func (b *SoundBank) acquire(sound string, pin bool) (*bankEntry, error) {
+	b.mtx.Lock()
+	e, ok := b.sounds[sound]
+	if !ok {
+		e = &bankEntry{ready: make(chan struct{})}
+		b.sounds[sound] = e
+	}
+	e.refs.Add(1)
+	if pin {
+		e.pins++
+	}
+	b.mtx.Unlock()
+
+	if !ok {
+		if s, err := b.load(sound); err != nil {
+			e.err = debug.ErrorWrapf(err, "Failed to load sound %q", sound)
+		} else {
+			e.asset = b.mixer.convert(s)
+		}
+		close(e.ready)
+	}
+	<-e.ready
+
+	if e.err != nil {
+		b.mtx.Lock()
+		// forget the failed entry so the next call tries again
+		if b.sounds[sound] == e {
+			delete(b.sounds, sound)
+		}
+		if pin {
+			e.pins--
+		}
+		e.refs.Add(-1)
+		b.mtx.Unlock()
+		return nil, e.err
+	}
+
+	return e, nil
+}
+
+// decodes and pins the sounds so they stay cached until Unload, on error
+// sounds loaded by this call are unpinned again
+func (b *SoundBank) Load(sounds ...string) error {
+	for i, s := range sounds {
+		if _, err := b.acquire(s, true); err != nil {
+			b.Unload(sounds[:i]...)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// releases the pins taken by Load, the sounds are freed by Trim once no
+// voice is playing them. Sounds that are not pinned are ignored.
+func (b *SoundBank) Unload(sounds ...string) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	for _, s := range sounds {
+		e, ok := b.sounds[s]
+		if !ok || e.pins == 0 {
+			debug.WPrintf("Unload of sound %q that is not loaded", s)
+			continue
+		}
+		e.pins--
+		e.refs.Add(-1)
+	}
+}
+
+// frees every sound that is neither pinned nor playing, returns the bytes freed
+func (b *SoundBank) Trim() int {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	freed := 0
+	for name, e := range b.sounds {
+		// entries still loading have a reference taken
+		if e.refs.Load() <= 0 {
+			freed += assetBytes(e.asset)
+			delete(b.sounds, name)
+		}
+	}
+
+	return freed
+}
+
+// returns the bytes used by the decoded samples of every cached sound
+func (b *SoundBank) MemoryUsage() int {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	used := 0
+	for _, e := range b.sounds {
+		if e.loaded() {
+			used += assetBytes(e.asset)
+		}
+	}
+
+	return used
+}
+
+// plays a sound from the bank, loading it first if it is not cached
+func (b *SoundBank) Play(sound string, cfg PlayConfig) (*Voice, error) {
+	e, err := b.acquire(sound, false)
+	if err != nil {
+		return nil, err
+	}
+
+	v := newVoice(b.mixer, e.asset, cfg)
+	v.release = func() { e.refs.Add(-1) }
+	v.sound = sound
+	b.mixer.add(v)
+
+	return v, nil
+}
+
+func assetBytes(s audio.Asset) int {
+	return s.DurationSamples() * len(s.Track()) * 4
+}
@@ -0,0 +1,128 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mixer
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"goarrg.com/asset/audio"
+)
+
+func TestSoundBank(t *testing.T) {
+	loads := 0
+	b := NewSoundBank()
+	b.mixer = newTestMixer()
+	b.load = func(string) (audio.Asset, error) {
+		loads++
+		return constantAsset(0.5, 100), nil
+	}
+
+	if err := b.Load("a"); err != nil {
+		t.Fatal(err)
+	}
+
+	v1, _ := b.Play("a", PlayConfig{})
+	v2, _ := b.Play("a", PlayConfig{})
+	if _, err := b.Play("b", PlayConfig{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if loads != 2 {
+		t.Fatalf("Decoded %d times, want 2", loads)
+	}
+	if v1.src.(*assetSource).asset != v2.src.(*assetSource).asset {
+		t.Fatal("Voices do not share the cached asset")
+	}
+	if used := b.MemoryUsage(); used != 2*100*2*4 {
+		t.Fatalf("Memory usage %d, want %d", used, 2*100*2*4)
+	}
+
+	// unpinning more often than pinned must not release the voices' references
+	b.Unload("a")
+	b.Unload("a", "c")
+	if freed := b.Trim(); freed != 0 {
+		t.Fatalf("Trim freed %d bytes of playing sounds", freed)
+	}
+
+	b.mixer.mix(100)
+	if freed := b.Trim(); freed != 2*100*2*4 {
+		t.Fatalf("Trim freed %d bytes, want %d", freed, 2*100*2*4)
+	}
+	if used := b.MemoryUsage(); used != 0 {
+		t.Fatalf("Memory usage %d after Trim", used)
+	}
+}
+
+func TestSoundBankLoadUnlocked(t *testing.T) {
+	b := NewSoundBank()
+	b.mixer = newTestMixer()
+
+	slow := make(chan struct{})
+	var mtx sync.Mutex
+	loads := map[string]int{}
+	b.load = func(sound string) (audio.Asset, error) {
+		mtx.Lock()
+		loads[sound]++
+		mtx.Unlock()
+		if sound == "slow" {
+			<-slow
+		}
+		return constantAsset(0.5, 100), nil
+	}
+
+	var wg sync.WaitGroup
+	for range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := b.Play("slow", PlayConfig{}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+
+	// other sounds load while the slow one is still decoding
+	done := make(chan error)
+	go func() { done <- b.Load("fast") }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Load blocked by another sound loading")
+	}
+
+	if used := b.MemoryUsage(); used != 100*2*4 {
+		t.Fatalf("Memory usage %d while loading, want %d", used, 100*2*4)
+	}
+	if freed := b.Trim(); freed != 0 {
+		t.Fatalf("Trim freed %d bytes of sounds in use", freed)
+	}
+
+	close(slow)
+	wg.Wait()
+
+	if loads["slow"] != 1 {
+		t.Fatalf("Decoded %d times, want 1", loads["slow"])
+	}
+}
@@ -0,0 +1,112 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mixer
+
+import (
+	"goarrg.com/asset/audio"
+)
+
+/*
+source produces the frames a voice plays at the output frequency, it is only
+used by the audio thread once the voice was added to the mixer.
+*/
+type source interface {
+	channels() []audio.Channel
+	// fills every channel of dst with the next frames, returns how many
+	// were written, fewer than len(dst[0]) means the source ended
+	read(dst [][]float32) int
+	// returns true once every frame was read
+	ended() bool
+	seek(frame int)
+	setLoop(count int)
+	// loops repeat the frames from start to end instead of the whole source,
+	// in frames of the source, end 0 is the end of the source
+	setLoopRegion(start, end int)
+}
+
+// assetSource plays a fully decoded asset from memory
+type assetSource struct {
+	asset     audio.Asset
+	cursor    int
+	loops     int
+	loopStart int
+	loopEnd   int
+}
+
+func newAssetSource(s audio.Asset) *assetSource {
+	return &assetSource{asset: s}
+}
+
+func (s *assetSource) channels() []audio.Channel {
+	return s.asset.Spec().Channels
+}
+
+func (s *assetSource) read(dst [][]float32) int {
+	duration := s.asset.DurationSamples()
+	channels := s.asset.Spec().Channels
+	track := s.asset.Track()
+	written := 0
+
+	for written < len(dst[0]) {
+		end := duration
+		if s.loops != 0 && s.loopEnd > 0 {
+			end = min(s.loopEnd, duration)
+		}
+
+		if s.cursor >= end {
+			if s.loops == 0 || end <= s.loopStart {
+				break
+			}
+
+			s.cursor = s.loopStart
+			if s.loops > 0 {
+				s.loops--
+			}
+			continue
+		}
+
+		n := min(len(dst[0])-written, end-s.cursor)
+		for i, c := range channels {
+			copy(dst[i][written:written+n], track[c][s.cursor:s.cursor+n])
+		}
+
+		s.cursor += n
+		written += n
+	}
+
+	return written
+}
+
+func (s *assetSource) ended() bool {
+	return s.cursor >= s.asset.DurationSamples() && s.loops == 0
+}
+
+func (s *assetSource) seek(frame int) {
+	s.cursor = max(min(frame, s.asset.DurationSamples()-1), 0)
+}
+
+func (s *assetSource) setLoop(count int) {
+	s.loops = count
+}
+
+func (s *assetSource) setLoopRegion(start, end int) {
+	s.loopStart = max(min(start, s.asset.DurationSamples()), 0)
+	s.loopEnd = max(end, 0)
+}
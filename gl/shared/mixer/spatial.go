@@ -0,0 +1,190 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mixer
+
+import (
+	"math"
+
+	"goarrg.com/gmath"
+)
+
+// defaults used for zero fields of SpatialConfig, in world units
+const (
+	defaultMinDistance = 100
+	defaultMaxDistance = 2000
+)
+
+// Attenuation selects how a spatial voice gets quieter with distance
+type Attenuation uint8
+
+const (
+	// MinDistance / distance, how sound falls off in the real world
+	AttenuationInverse Attenuation = iota
+	// falls in a straight line to silence at MaxDistance
+	AttenuationLinear
+	// (distance / MinDistance) ^ -Rolloff
+	AttenuationExponential
+)
+
+/*
+SpatialConfig places a voice in world space, its gain and pan follow the
+voice's position relative to the Listener. Distances are in world units, for
+gl2d the same units as sprite positions.
+*/
+type SpatialConfig struct {
+	Pos gmath.Point3f64
+	// world units per second, only used for doppler
+	Velocity gmath.Vector3f64
+
+	Attenuation Attenuation
+	// the voice plays at full gain up to this distance, 0 uses 100
+	MinDistance float64
+	// the gain stops changing past this distance, 0 uses 2000
+	MaxDistance float64
+	// scales how fast the gain falls, 0 is treated as 1
+	Rolloff float64
+	// in world units per second, 0 disables the doppler pitch shift
+	SpeedOfSound float64
+}
+
+// Listener is where spatial voices are heard from, usually the camera
+type Listener struct {
+	Pos gmath.Point3f64
+	// world units per second, only used for doppler
+	Velocity gmath.Vector3f64
+	// rotation in radians of the listener's view, matching gl2d.Camera.Rotation
+	Rotation float64
+}
+
+func (c *SpatialConfig) defaults() {
+	if c.MinDistance <= 0 {
+		c.MinDistance = defaultMinDistance
+	}
+	if c.MaxDistance <= 0 {
+		c.MaxDistance = defaultMaxDistance
+	}
+	c.MaxDistance = max(c.MaxDistance, c.MinDistance)
+	if c.Rolloff <= 0 {
+		c.Rolloff = 1
+	}
+}
+
+// returns the gain of a voice at the given distance
+func (c *SpatialConfig) attenuate(distance float64) float32 {
+	d := min(max(distance, c.MinDistance), c.MaxDistance)
+
+	switch c.Attenuation {
+	case AttenuationLinear:
+		if c.MaxDistance == c.MinDistance {
+			return 1
+		}
+		return float32(max(1-c.Rolloff*(d-c.MinDistance)/(c.MaxDistance-c.MinDistance), 0))
+	case AttenuationExponential:
+		return float32(math.Pow(d/c.MinDistance, -c.Rolloff))
+	default:
+		return float32(c.MinDistance / (c.MinDistance + c.Rolloff*(d-c.MinDistance)))
+	}
+}
+
+/*
+spatialize returns the gain, pan and playback rate of a voice configured by c
+as heard by l. Pan is the sideways offset in the listener's view divided by
+the distance so voices closer than MinDistance move towards the center.
+*/
+func (c *SpatialConfig) spatialize(l Listener) (gain, pan float32, rate float64) {
+	offset := gmath.Vector3f64(c.Pos).Subtract(gmath.Vector3f64(l.Pos))
+	distance := offset.Magnitude()
+
+	sin, cos := math.Sincos(-l.Rotation)
+	side := offset.X*cos - offset.Y*sin
+
+	gain = c.attenuate(distance)
+	pan = float32(side / max(distance, c.MinDistance))
+	rate = 1
+
+	if c.SpeedOfSound > 0 && distance > 0 {
+		dir := offset.ScaleInverseUniform(distance)
+		// listener speed towards the voice and voice speed away from the
+		// listener, capped below the speed of sound
+		limit := c.SpeedOfSound / 2
+		towards := min(max(l.Velocity.Dot(dir), -limit), limit)
+		away := min(max(c.Velocity.Dot(dir), -limit), limit)
+		rate = (c.SpeedOfSound + towards) / (c.SpeedOfSound + away)
+	}
+
+	return gain, pan, rate
+}
+
+// updates the gain, pan and rate of a spatial voice, only called by the audio thread
+func (a *audioMixer) spatialize(v *Voice) {
+	gain, pan, rate := v.spatial.spatialize(a.listener)
+	left, right := panGains(pan)
+
+	v.attenuation.set(gain, a.rampSamples)
+	v.panLeft.set(left, a.rampSamples)
+	v.panRight.set(right, a.rampSamples)
+
+	v.doppler = float32(rate)
+	if r, ok := v.src.(*rateSource); ok {
+		r.setRate(v.pitch*v.doppler, a.rampSamples)
+	}
+}
+
+// starts a spatial voice where it is heard from instead of ramping there
+func (a *audioMixer) place(v *Voice) {
+	gain, pan, rate := v.spatial.spatialize(a.listener)
+	left, right := panGains(pan)
+
+	v.attenuation = newRamp(gain)
+	v.panLeft = newRamp(left)
+	v.panRight = newRamp(right)
+
+	v.doppler = float32(rate)
+	if r, ok := v.src.(*rateSource); ok {
+		r.setRate(v.pitch*v.doppler, 0)
+	}
+}
+
+// sets where spatial voices are heard from, call it every frame with the camera
+func SetListener(l Listener) {
+	Mixer.commands.push(func() {
+		Mixer.listener = l
+	})
+}
+
+/*
+PlaySoundAt plays a sound once at a world position with the default
+SpatialConfig, use Voice.SetPosition to move it.
+*/
+func PlaySoundAt(sound string, pos gmath.Point3f64) (*Voice, error) {
+	return PlaySoundWithConfig(sound, PlayConfig{Spatial: &SpatialConfig{Pos: pos}})
+}
+
+// moves a spatial voice, it is a no-op for voices started without PlayConfig.Spatial
+func (v *Voice) SetPosition(pos gmath.Point3f64, velocity gmath.Vector3f64) {
+	if v.spatial == nil {
+		return
+	}
+
+	v.mixer.commands.push(func() {
+		v.spatial.Pos = pos
+		v.spatial.Velocity = velocity
+	})
+}
@@ -0,0 +1,139 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mixer
+
+import (
+	"math"
+	"testing"
+
+	"goarrg.com/asset/audio"
+	"goarrg.com/gmath"
+)
+
+func TestAttenuation(t *testing.T) {
+	tests := []struct {
+		cfg      SpatialConfig
+		distance float64
+		want     float32
+	}{
+		{SpatialConfig{}, 50, 1},
+		{SpatialConfig{}, 200, 0.5},
+		{SpatialConfig{}, 400, 0.25},
+		{SpatialConfig{Rolloff: 2}, 200, 1.0 / 3},
+		// clamped to MaxDistance
+		{SpatialConfig{MaxDistance: 400}, 1000, 0.25},
+		{SpatialConfig{Attenuation: AttenuationLinear, MinDistance: 100, MaxDistance: 300}, 200, 0.5},
+		{SpatialConfig{Attenuation: AttenuationLinear, MinDistance: 100, MaxDistance: 300}, 500, 0},
+		{SpatialConfig{Attenuation: AttenuationExponential}, 400, 0.25},
+		{SpatialConfig{Attenuation: AttenuationExponential, Rolloff: 2}, 200, 0.25},
+	}
+
+	for _, test := range tests {
+		test.cfg.defaults()
+		assertNear(t, "attenuation", test.cfg.attenuate(test.distance), test.want)
+	}
+}
+
+func TestSpatialPan(t *testing.T) {
+	tests := []struct {
+		pos      gmath.Point3f64
+		rotation float64
+		pan      float32
+	}{
+		{gmath.Point3f64{X: 1000}, 0, 1},
+		{gmath.Point3f64{X: -1000}, 0, -1},
+		{gmath.Point3f64{Y: 1000}, 0, 0},
+		{gmath.Point3f64{X: 1000, Y: 1000}, 0, math.Sqrt2 / 2},
+		// closer than MinDistance moves towards the center
+		{gmath.Point3f64{X: 50}, 0, 0.5},
+		// the listener turned so the voice to its right is in front
+		{gmath.Point3f64{X: 1000}, math.Pi / 2, 0},
+		{gmath.Point3f64{Y: 1000}, math.Pi / 2, 1},
+	}
+
+	for _, test := range tests {
+		cfg := SpatialConfig{Pos: test.pos}
+		cfg.defaults()
+
+		_, pan, rate := cfg.spatialize(Listener{Rotation: test.rotation})
+		assertNear(t, "pan", pan, test.pan)
+		if rate != 1 {
+			t.Fatalf("Rate %f without doppler", rate)
+		}
+	}
+}
+
+func TestDoppler(t *testing.T) {
+	tests := []struct {
+		voice, listener gmath.Vector3f64
+		rate            float64
+	}{
+		{gmath.Vector3f64{}, gmath.Vector3f64{}, 1},
+		// moving sideways does not change the distance
+		{gmath.Vector3f64{Y: 100}, gmath.Vector3f64{}, 1},
+		{gmath.Vector3f64{X: -100}, gmath.Vector3f64{}, 1000.0 / 900},
+		{gmath.Vector3f64{X: 100}, gmath.Vector3f64{}, 1000.0 / 1100},
+		{gmath.Vector3f64{}, gmath.Vector3f64{X: 100}, 1100.0 / 1000},
+		// capped at half the speed of sound
+		{gmath.Vector3f64{X: -5000}, gmath.Vector3f64{}, 2},
+	}
+
+	for _, test := range tests {
+		cfg := SpatialConfig{Pos: gmath.Point3f64{X: 500}, Velocity: test.voice, SpeedOfSound: 1000}
+		cfg.defaults()
+
+		_, _, rate := cfg.spatialize(Listener{Velocity: test.listener})
+		if math.Abs(rate-test.rate) > 1e-9 {
+			t.Fatalf("Rate %f, want %f", rate, test.rate)
+		}
+	}
+}
+
+func TestSpatialVoice(t *testing.T) {
+	a := newTestMixer()
+	a.listener = Listener{Pos: gmath.Point3f64{X: 100, Y: 100}}
+
+	cfg := SpatialConfig{Pos: gmath.Point3f64{X: 300, Y: 100}}
+	v := a.play(constantAsset(0.5, testFrequency), PlayConfig{Spatial: &cfg})
+	a.mix(10)
+
+	// hard right at twice the min distance
+	assertNear(t, "left", a.masterTrack[audio.ChannelLeft][0], 0)
+	assertNear(t, "right", a.masterTrack[audio.ChannelRight][0], 0.5*0.5*math.Sqrt2)
+
+	// the config is copied
+	cfg.Pos.X = 100
+	a.mix(a.rampSamples + 10)
+	assertNear(t, "copied", a.masterTrack[audio.ChannelRight][a.rampSamples], 0.5*0.5*math.Sqrt2)
+
+	v.SetPosition(gmath.Point3f64{X: 100, Y: 500}, gmath.Vector3f64{})
+	a.mix(a.rampSamples + 10)
+	assertNear(t, "moved", a.masterTrack[audio.ChannelLeft][a.rampSamples], 0.5*0.25)
+	assertNear(t, "moved", a.masterTrack[audio.ChannelRight][a.rampSamples], 0.5*0.25)
+
+	// the listener follows
+	a.listener.Pos.Y = 500
+	a.mix(a.rampSamples + 10)
+	assertNear(t, "listener", a.masterTrack[audio.ChannelLeft][a.rampSamples], 0.5)
+
+	if _, ok := v.src.(*rateSource); ok {
+		t.Fatal("Voice without doppler was wrapped in a rateSource")
+	}
+}
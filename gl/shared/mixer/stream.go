@@ -0,0 +1,415 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mixer
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"goarrg.com/asset"
+	"goarrg.com/asset/audio"
+	"goarrg.com/debug"
+)
+
+const (
+	// seconds of decoded audio a stream buffers ahead of the mixer
+	streamBufferSeconds = 1
+	// frames decoded per step of the background goroutine
+	streamChunkFrames = 4096
+)
+
+/*
+streamSource plays a file decoded in chunks by a background goroutine into a
+ring buffer, so only about a second of audio is in memory at any time. The
+goroutine is the only writer and the audio thread the only reader, positions
+are frame counters that only grow and are shared through atomics.
+*/
+type streamSource struct {
+	name     string
+	decoder  *wavDecoder
+	closer   io.Closer
+	chans    []audio.Channel
+	resample *streamResampler
+	// output frequency
+	frequency int
+
+	ring     [][]float32
+	readPos  atomic.Int64
+	writePos atomic.Int64
+	// writePos at which the file ends, -1 while more frames will follow
+	endPos atomic.Int64
+	// frames before this belong to the position playing before a seek
+	discardBefore atomic.Int64
+
+	loops     atomic.Int64
+	loopStart atomic.Int64
+	loopEnd   atomic.Int64
+	// frame of the file to seek to, -1 once the goroutine finished the last seek
+	seekTo atomic.Int64
+
+	// frames of silence played because the ring ran dry
+	underruns atomic.Uint64
+	reported  uint64
+
+	refill chan struct{}
+	// signaled after every fill of the goroutine, used by offline rendering
+	filled   chan struct{}
+	quit     chan struct{}
+	quitOnce sync.Once
+
+	// only accessed by the goroutine after start
+	chunk   [][]float32
+	out     [][]float32
+	limited [][]float32
+}
+
+// opens a WAV file as a stream converted to the given frequency
+func openStream(file string, frequency int, quality ResampleQuality) (*streamSource, error) {
+	f, err := asset.Load(file)
+	if err != nil {
+		return nil, debug.ErrorWrapf(err, "Failed to open stream %q", file)
+	}
+
+	s, err := newStreamSource(file, f, int64(f.Size()), frequency, quality)
+	if err != nil {
+		f.Close()
+		return nil, debug.ErrorWrapf(err, "Failed to open stream %q", file)
+	}
+
+	s.closer = f
+	return s, nil
+}
+
+func newStreamSource(name string, r io.ReaderAt, size int64, frequency int, quality ResampleQuality) (*streamSource, error) {
+	d, err := newWAVDecoder(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	channels := len(d.spec.Channels)
+	s := &streamSource{
+		name:    name,
+		decoder: d,
+		chans:   d.spec.Channels,
+		ring:    make([][]float32, channels),
+		chunk:   make([][]float32, channels),
+		out:     make([][]float32, channels),
+		limited: make([][]float32, channels),
+		refill:  make(chan struct{}, 1),
+		filled:  make(chan struct{}, 1),
+		quit:    make(chan struct{}),
+	}
+
+	if frequency > 0 && frequency != d.spec.Frequency {
+		s.resample = newStreamResampler(channels, d.spec.Frequency, frequency, quality)
+	} else {
+		frequency = d.spec.Frequency
+	}
+	s.frequency = frequency
+
+	for c := range s.ring {
+		s.ring[c] = make([]float32, frequency*streamBufferSeconds)
+		s.chunk[c] = make([]float32, streamChunkFrames)
+		s.out[c] = make([]float32, streamChunkFrames)
+	}
+
+	s.endPos.Store(-1)
+	s.seekTo.Store(-1)
+
+	return s, nil
+}
+
+// fills the ring before the first mix and starts the decoder goroutine
+func (s *streamSource) start() {
+	s.fill()
+
+	go func() {
+		if s.closer != nil {
+			defer s.closer.Close()
+		}
+
+		for {
+			select {
+			case <-s.quit:
+				return
+			case <-s.refill:
+				s.fill()
+
+				select {
+				case s.filled <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+}
+
+// stops the goroutine which then closes the file, never blocks so it can be
+// called from the audio thread
+func (s *streamSource) close() {
+	s.quitOnce.Do(func() {
+		close(s.quit)
+	})
+}
+
+// returns true once close was called
+func (s *streamSource) closed() bool {
+	select {
+	case <-s.quit:
+		return true
+	default:
+		return false
+	}
+}
+
+// wakes the goroutine if the ring is less than half full, never blocks
+func (s *streamSource) requestRefill() {
+	if s.writePos.Load()-s.readFrom() > int64(len(s.ring[0])/2) {
+		return
+	}
+
+	select {
+	case s.refill <- struct{}{}:
+	default:
+	}
+}
+
+/*
+wait blocks until the next frames can be read without an underrun, rendering
+offline has no deadline so it waits for the goroutine instead of playing
+silence. Only called from the reader while the goroutine is running.
+*/
+func (s *streamSource) wait(frames int) {
+	for {
+		available := s.writePos.Load() - s.readFrom()
+		ended := s.endPos.Load() >= 0 && s.loops.Load() == 0
+		if !s.seeking() && (available >= int64(frames) || ended) {
+			return
+		}
+
+		select {
+		case s.refill <- struct{}{}:
+		default:
+		}
+
+		select {
+		case <-s.filled:
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// decodes until the ring is full or the file ended
+func (s *streamSource) fill() {
+	if frame := s.seekTo.Load(); frame >= 0 {
+		s.decoder.seek(int(frame))
+		if s.resample != nil {
+			s.resample.reset()
+		}
+		s.endPos.Store(-1)
+		s.discardBefore.Store(s.writePos.Load())
+
+		// a seek requested meanwhile stays pending for the next fill
+		s.seekTo.CompareAndSwap(frame, -1)
+	}
+
+	// a loop was requested after the end was decoded
+	if s.endPos.Load() >= 0 && s.loops.Load() != 0 {
+		s.decoder.seek(int(s.loopStart.Load()))
+		if s.resample != nil {
+			s.resample.reset()
+		}
+		s.endPos.Store(-1)
+	}
+
+	for s.endPos.Load() < 0 {
+		free := len(s.ring[0]) - int(s.writePos.Load()-s.readFrom())
+		if free <= 0 {
+			return
+		}
+
+		n, ended := s.decode(min(free, streamChunkFrames))
+		s.push(n)
+
+		if ended {
+			s.endPos.Store(s.writePos.Load())
+		}
+	}
+}
+
+// decodes up to frames converted frames into out, returns true once the file
+// ended and no loop is left
+func (s *streamSource) decode(frames int) (int, bool) {
+	for c := range s.out {
+		s.out[c] = s.out[c][:frames]
+	}
+
+	if s.resample == nil {
+		n := s.decodeLooped(s.out)
+		return n, n == 0
+	}
+
+	for {
+		if n := s.resample.read(s.out, false); n > 0 {
+			return n, false
+		}
+
+		n := s.decodeLooped(s.chunk)
+		if n == 0 {
+			n = s.resample.read(s.out, true)
+			return n, n == 0
+		}
+
+		s.resample.write(s.chunk, n)
+	}
+}
+
+// reads the next decoded frames at the file frequency, rewinding to the loop
+// start for every loop left so loops are gapless
+func (s *streamSource) decodeLooped(dst [][]float32) int {
+	for {
+		loops := s.loops.Load()
+		start, end := int(s.loopStart.Load()), int(s.loopEnd.Load())
+		if loops == 0 || end <= 0 {
+			end = s.decoder.frames
+		}
+		end = min(end, s.decoder.frames)
+
+		if frames := min(len(dst[0]), end-s.decoder.cursor); frames > 0 {
+			for c := range dst {
+				s.limited[c] = dst[c][:frames]
+			}
+
+			n, err := s.decoder.read(s.limited)
+			if err != nil {
+				debug.EPrintf("Stopping stream %q: %v", s.name, err)
+				return 0
+			}
+			if n > 0 {
+				return n
+			}
+		}
+
+		if loops == 0 || end <= start {
+			return 0
+		}
+		if loops > 0 {
+			s.loops.CompareAndSwap(loops, loops-1)
+		}
+
+		s.decoder.seek(start)
+	}
+}
+
+// copies n frames of out into the ring
+func (s *streamSource) push(n int) {
+	size := int64(len(s.ring[0]))
+	pos := s.writePos.Load()
+
+	for c, ring := range s.ring {
+		start := int(pos % size)
+		copied := copy(ring[start:], s.out[c][:n])
+		copy(ring, s.out[c][copied:n])
+	}
+
+	s.writePos.Store(pos + int64(n))
+}
+
+func (s *streamSource) channels() []audio.Channel {
+	return s.chans
+}
+
+func (s *streamSource) read(dst [][]float32) int {
+	frames := len(dst[0])
+
+	// the ring holds the old position until the goroutine handled the seek
+	if s.seeking() {
+		s.readPos.Store(max(s.readPos.Load(), s.writePos.Load()))
+		for c := range dst {
+			clear(dst[c])
+		}
+		return frames
+	}
+
+	pos := s.readFrom()
+	n := min(frames, int(s.writePos.Load()-pos))
+	size := int64(len(s.ring[0]))
+
+	for c, ring := range s.ring {
+		start := int(pos % size)
+		copied := copy(dst[c][:n], ring[start:])
+		copy(dst[c][copied:n], ring)
+	}
+
+	pos += int64(n)
+	s.readPos.Store(pos)
+
+	if n < frames {
+		if end := s.endPos.Load(); end >= 0 && pos >= end {
+			return n
+		}
+
+		// keep playing silence so the voice survives until the ring catches up
+		for c := range dst {
+			clear(dst[c][n:])
+		}
+		s.underruns.Add(uint64(frames - n))
+	}
+
+	return frames
+}
+
+func (s *streamSource) ended() bool {
+	end := s.endPos.Load()
+	return end >= 0 && s.readPos.Load() >= end && !s.seeking()
+}
+
+// returns true until the goroutine handled the last seek
+func (s *streamSource) seeking() bool {
+	return s.seekTo.Load() >= 0
+}
+
+// returns the next frame the reader plays, frames decoded before a seek are skipped
+func (s *streamSource) readFrom() int64 {
+	return max(s.readPos.Load(), s.discardBefore.Load())
+}
+
+// frame is at the output frequency, the goroutine does the actual seek
+func (s *streamSource) seek(frame int) {
+	frame = int(int64(max(frame, 0)) * int64(s.decoder.spec.Frequency) / int64(s.frequency))
+	s.seekTo.Store(int64(min(frame, max(s.decoder.frames-1, 0))))
+
+	select {
+	case s.refill <- struct{}{}:
+	default:
+	}
+}
+
+func (s *streamSource) setLoop(count int) {
+	s.loops.Store(int64(count))
+}
+
+// start and end are in frames of the file
+func (s *streamSource) setLoopRegion(start, end int) {
+	s.loopStart.Store(int64(max(min(start, s.decoder.frames), 0)))
+	s.loopEnd.Store(int64(max(end, 0)))
+}
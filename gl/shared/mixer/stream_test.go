@@ -0,0 +1,329 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mixer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"runtime"
+	"testing"
+
+	"goarrg.com/asset/audio"
+)
+
+// encodes the channels as a WAV file with the given sample format
+func wavFile(format uint16, bits, frequency int, channels [][]float32) []byte {
+	frameBytes := len(channels) * bits / 8
+	data := new(bytes.Buffer)
+
+	for i := range channels[0] {
+		for _, c := range channels {
+			v := float64(c[i])
+			switch {
+			case format == wavFormatFloat && bits == 32:
+				binary.Write(data, binary.LittleEndian, float32(v))
+			case format == wavFormatFloat:
+				binary.Write(data, binary.LittleEndian, v)
+			case bits == 8:
+				data.WriteByte(byte(int(v*128) + 128))
+			case bits == 16:
+				binary.Write(data, binary.LittleEndian, int16(v*(1<<15)))
+			case bits == 24:
+				s := int32(v * (1 << 23))
+				data.Write([]byte{byte(s), byte(s >> 8), byte(s >> 16)})
+			default:
+				binary.Write(data, binary.LittleEndian, int32(v*(1<<31)))
+			}
+		}
+	}
+
+	out := new(bytes.Buffer)
+	out.WriteString("RIFF")
+	binary.Write(out, binary.LittleEndian, uint32(4+8+16+8+data.Len()))
+	out.WriteString("WAVEfmt ")
+	binary.Write(out, binary.LittleEndian, uint32(16))
+	binary.Write(out, binary.LittleEndian, format)
+	binary.Write(out, binary.LittleEndian, uint16(len(channels)))
+	binary.Write(out, binary.LittleEndian, uint32(frequency))
+	binary.Write(out, binary.LittleEndian, uint32(frequency*frameBytes))
+	binary.Write(out, binary.LittleEndian, uint16(frameBytes))
+	binary.Write(out, binary.LittleEndian, uint16(bits))
+	out.WriteString("data")
+	binary.Write(out, binary.LittleEndian, uint32(data.Len()))
+	out.Write(data.Bytes())
+
+	return out.Bytes()
+}
+
+// returns a 16 bit stereo stream where frame i of each loop holds i/32768
+func rampStream(t *testing.T, frames, frequency, outFrequency int) *streamSource {
+	t.Helper()
+
+	ramp := make([]float32, frames)
+	for i := range ramp {
+		ramp[i] = float32(i) / (1 << 15)
+	}
+
+	file := wavFile(wavFormatPCM, 16, frequency, [][]float32{ramp, ramp})
+	s, err := newStreamSource("ramp", bytes.NewReader(file), int64(len(file)), outFrequency, ResampleSinc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s
+}
+
+func makeBlock(channels, frames int) [][]float32 {
+	block := make([][]float32, channels)
+	for c := range block {
+		block[c] = make([]float32, frames)
+	}
+	return block
+}
+
+func TestWAVDecoderFormats(t *testing.T) {
+	values := []float32{0, 0.5, -0.5, 0.25, -1}
+	formats := []struct {
+		format uint16
+		bits   int
+	}{
+		{wavFormatPCM, 8},
+		{wavFormatPCM, 16},
+		{wavFormatPCM, 24},
+		{wavFormatPCM, 32},
+		{wavFormatFloat, 32},
+		{wavFormatFloat, 64},
+	}
+
+	for _, f := range formats {
+		file := wavFile(f.format, f.bits, testFrequency, [][]float32{values})
+		d, err := newWAVDecoder(bytes.NewReader(file), int64(len(file)))
+		if err != nil {
+			t.Fatalf("%#x %d bits: %v", f.format, f.bits, err)
+		}
+
+		if len(d.spec.Channels) != 1 || d.spec.Frequency != testFrequency || d.frames != len(values) {
+			t.Fatalf("%#x %d bits: decoded %+v with %d frames", f.format, f.bits, d.spec, d.frames)
+		}
+
+		// decode one frame at a time to cover chunk boundaries
+		for _, want := range values {
+			block := makeBlock(1, 1)
+			if n, err := d.read(block); n != 1 || err != nil {
+				t.Fatalf("%#x %d bits: read %d frames: %v", f.format, f.bits, n, err)
+			}
+			assertNear(t, "sample", block[0][0], want)
+		}
+
+		if n, _ := d.read(makeBlock(1, 1)); n != 0 {
+			t.Fatalf("%#x %d bits: read %d frames past the end", f.format, f.bits, n)
+		}
+	}
+}
+
+func TestWAVDecoderInvalid(t *testing.T) {
+	files := [][]byte{
+		[]byte("RIFF\x00\x00\x00\x00WAVE"),
+		[]byte("OggS\x00\x00\x00\x00\x00\x00\x00\x00"),
+		wavFile(wavFormatPCM, 16, testFrequency, [][]float32{{0}, {0}, {0}}),
+	}
+
+	for i, file := range files {
+		if _, err := newWAVDecoder(bytes.NewReader(file), int64(len(file))); err == nil {
+			t.Fatalf("File %d decoded without error", i)
+		}
+	}
+}
+
+func TestWAVDecoderChunkSizes(t *testing.T) {
+	file := wavFile(wavFormatPCM, 16, testFrequency, [][]float32{make([]float32, 100)})
+	// a fmt chunk claiming almost 4GB
+	binary.LittleEndian.PutUint32(file[16:20], 0xFFFFFFF0)
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	_, err := newWAVDecoder(bytes.NewReader(file), int64(len(file)))
+	runtime.ReadMemStats(&after)
+
+	if err == nil {
+		t.Fatal("Decoded a file whose fmt chunk runs past its end")
+	}
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 1<<16 {
+		t.Fatalf("Allocated %d bytes for a chunk header", allocated)
+	}
+}
+
+func TestStreamGaplessLoop(t *testing.T) {
+	const frames = 1000
+	s := rampStream(t, frames, 1000, 0)
+	s.setLoop(2)
+
+	block := makeBlock(2, 300)
+	played := 0
+
+	for !s.ended() {
+		s.fill()
+		n := s.read(block)
+
+		for i := 0; i < n; i++ {
+			want := float32((played+i)%frames) / (1 << 15)
+			if block[0][i] != want || block[1][i] != want {
+				t.Fatalf("Frame %d = %f, want %f", played+i, block[0][i], want)
+			}
+		}
+
+		played += n
+		if n < len(block[0]) {
+			break
+		}
+	}
+
+	if played != 3*frames {
+		t.Fatalf("Played %d frames, want %d", played, 3*frames)
+	}
+	if s.underruns.Load() != 0 {
+		t.Fatalf("%d frames underrun", s.underruns.Load())
+	}
+}
+
+func TestStreamSeek(t *testing.T) {
+	s := rampStream(t, 1000, 1000, 0)
+	s.fill()
+
+	block := makeBlock(2, 10)
+	s.read(block)
+	s.seek(500)
+
+	// silence until the decoder handled the seek
+	s.read(block)
+	for _, v := range block[0] {
+		assertNear(t, "sample while seeking", v, 0)
+	}
+
+	s.fill()
+	s.read(block)
+	assertNear(t, "sample after seek", block[0][0], 500.0/(1<<15))
+}
+
+func TestStreamSeekFullRing(t *testing.T) {
+	// longer than the ring so it is full before the seek
+	s := rampStream(t, 4000, 1000, 0)
+	s.fill()
+	if free := len(s.ring[0]) - int(s.writePos.Load()-s.readFrom()); free != 0 {
+		t.Fatalf("Ring has %d free frames before the seek", free)
+	}
+
+	// the stale frames are skipped so the fill after the seek is not starved
+	s.seek(100)
+	s.fill()
+	if s.seeking() {
+		t.Fatal("Seek still pending after fill")
+	}
+	if n := s.writePos.Load() - s.readFrom(); n != int64(len(s.ring[0])) {
+		t.Fatalf("Filled %d frames after the seek, want %d", n, len(s.ring[0]))
+	}
+
+	block := makeBlock(2, 10)
+	s.read(block)
+	assertNear(t, "sample after seek", block[0][0], 100.0/(1<<15))
+}
+
+func TestStreamResample(t *testing.T) {
+	const frames = 4800
+	s := rampStream(t, frames, 48000, testFrequency)
+
+	ramp := make([]float32, frames)
+	for i := range ramp {
+		ramp[i] = float32(i) / (1 << 15)
+	}
+	want := Resample(newTrackAsset(audio.Spec{Channels: audio.ChannelsMono(), Frequency: 48000},
+		audio.Track{audio.ChannelLeft: ramp}), testFrequency, ResampleSinc).Track()[audio.ChannelLeft]
+
+	// odd block sizes so the resampler sees input split at arbitrary points
+	block := makeBlock(2, 37)
+	var got []float32
+
+	for !s.ended() {
+		s.fill()
+		n := s.read(block)
+		got = append(got, block[0][:n]...)
+		if n < len(block[0]) {
+			break
+		}
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("Streamed %d frames, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if math.Abs(float64(got[i]-want[i])) > 1e-4 {
+			t.Fatalf("Frame %d = %f, want %f", i, got[i], want[i])
+		}
+	}
+}
+
+func TestStreamUnderrun(t *testing.T) {
+	s := rampStream(t, 5000, 1000, 0)
+	s.setLoop(LoopForever)
+	s.start()
+	defer s.close()
+
+	// the ring holds one second so reading two without a refill must run dry
+	block := makeBlock(2, 2000)
+	if n := s.read(block); n != len(block[0]) {
+		t.Fatalf("Read %d frames, an underrun must not end the stream", n)
+	}
+	if s.underruns.Load() != 1000 {
+		t.Fatalf("%d frames underrun, want 1000", s.underruns.Load())
+	}
+	for _, v := range block[0][1000:] {
+		assertNear(t, "underrun sample", v, 0)
+	}
+}
+
+func TestStreamConcurrentRefill(t *testing.T) {
+	const frames = 777
+	s := rampStream(t, frames, 1000, 0)
+	s.setLoop(LoopForever)
+	s.start()
+	defer s.close()
+
+	block := makeBlock(2, 123)
+	played := 0
+
+	for played < 20000 {
+		s.requestRefill()
+
+		// only read once the decoder caught up so the test never underruns
+		if s.writePos.Load()-s.readPos.Load() < int64(len(block[0])) {
+			runtime.Gosched()
+			continue
+		}
+
+		s.read(block)
+		for i, v := range block[1] {
+			if want := float32((played+i)%frames) / (1 << 15); v != want {
+				t.Fatalf("Frame %d = %f, want %f", played+i, v, want)
+			}
+		}
+		played += len(block[0])
+	}
+}
@@ -0,0 +1,327 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mixer
+
+import (
+	"math"
+
+	"goarrg.com/asset/audio"
+)
+
+// the lowest frequency a slide can reach, the highest is just below nyquist
+const minSynthFrequency = 20
+
+// noise picks a new random value this many times per period so it follows the pitch
+const noiseSteps = 16
+
+type Waveform uint8
+
+const (
+	WaveSine Waveform = iota
+	WaveSquare
+	WaveSaw
+	WaveTriangle
+	WaveNoise
+)
+
+/*
+Envelope shapes the gain of a note in seconds. The gain rises to 1 over
+Attack, falls to Sustain over Decay, stays there for Hold and falls to
+silence over Release. Without a Decay the note holds and releases from 1 and
+Sustain is ignored.
+*/
+type Envelope struct {
+	Attack  float64
+	Decay   float64
+	Sustain float32
+	Hold    float64
+	Release float64
+}
+
+/*
+Patch describes a synthesized sound effect in the spirit of sfxr, small enough
+to generate retro effects and UI blips at runtime instead of shipping WAVs.
+Frequencies are in Hz and times in seconds.
+*/
+type Patch struct {
+	Wave      Waveform
+	Frequency float64
+	// octaves per second the frequency slides by, negative slides down
+	Slide float64
+	// vibrato depth in semitones and rate in Hz
+	VibratoDepth float64
+	VibratoRate  float64
+	// multiplies the frequency once after ArpeggioDelay, 0 disables it
+	Arpeggio      float64
+	ArpeggioDelay float64
+	// fraction of the period a square wave is high, 0 is treated as 0.5
+	Duty float64
+	// change of Duty per second
+	DutySweep float64
+	// frequency modulation by a sine at Frequency * FMRatio, FMIndex is the
+	// modulation depth in radians, 0 disables it
+	FMRatio  float64
+	FMIndex  float64
+	Envelope Envelope
+	// linear gain, 0 is treated as 1
+	Gain float32
+	// seeds the noise so a patch always sounds the same
+	Seed uint32
+}
+
+// returns the patch with zero fields replaced by their defaults
+func (p Patch) normalized() Patch {
+	if p.Duty <= 0 {
+		p.Duty = 0.5
+	}
+	if p.Gain == 0 {
+		p.Gain = 1
+	}
+	if p.Seed == 0 {
+		p.Seed = 1
+	}
+	p.Frequency = max(p.Frequency, minSynthFrequency)
+	p.Envelope.Sustain = min(max(p.Envelope.Sustain, 0), 1)
+	return p
+}
+
+// returns the length of the note in seconds
+func (p Patch) Duration() float64 {
+	e := p.Envelope
+	return max(e.Attack, 0) + max(e.Decay, 0) + max(e.Hold, 0) + max(e.Release, 0)
+}
+
+// envelope returns the gain at t seconds into the note
+func (e *Envelope) at(t float64) float32 {
+	if t < e.Attack {
+		return float32(t / e.Attack)
+	}
+	t -= max(e.Attack, 0)
+
+	if t < e.Decay {
+		return 1 - (1-e.Sustain)*float32(t/e.Decay)
+	}
+	t -= max(e.Decay, 0)
+
+	// the level the attack or decay ended on
+	level := float32(1)
+	if e.Decay > 0 {
+		level = e.Sustain
+	}
+
+	if t < e.Hold {
+		return level
+	}
+	t -= max(e.Hold, 0)
+
+	if t < e.Release {
+		return level * float32(1-t/e.Release)
+	}
+	return 0
+}
+
+// synthSource generates a patch as a mono source at the output frequency
+type synthSource struct {
+	patch     Patch
+	frequency int
+	length    int
+	loops     int
+
+	// position and oscillator state of the note playing
+	cursor   int
+	phase    float64
+	modPhase float64
+	noise    float32
+	noiseAt  int
+	random   uint32
+}
+
+func newSynthSource(p Patch, frequency int) *synthSource {
+	s := &synthSource{
+		patch:     p.normalized(),
+		frequency: frequency,
+		length:    int(p.Duration() * float64(frequency)),
+	}
+	s.restart()
+	return s
+}
+
+// starts the note over, the noise keeps going so loops do not repeat exactly
+func (s *synthSource) restart() {
+	s.cursor = 0
+	s.phase = 0
+	s.modPhase = 0
+	s.noiseAt = -1
+	if s.random == 0 {
+		s.random = s.patch.Seed
+	}
+}
+
+// returns the next value of a xorshift generator in [-1, 1)
+func (s *synthSource) nextRandom() float32 {
+	s.random ^= s.random << 13
+	s.random ^= s.random >> 17
+	s.random ^= s.random << 5
+	return float32(s.random)/(1<<31) - 1
+}
+
+// returns the next sample of the note and advances the oscillators
+func (s *synthSource) next() float32 {
+	p := &s.patch
+	t := float64(s.cursor) / float64(s.frequency)
+
+	f := p.Frequency * math.Exp2(p.Slide*t)
+	if p.Arpeggio > 0 && t >= p.ArpeggioDelay {
+		f *= p.Arpeggio
+	}
+	if p.VibratoDepth != 0 {
+		f *= math.Exp2(p.VibratoDepth / 12 * math.Sin(2*math.Pi*p.VibratoRate*t))
+	}
+	f = min(max(f, minSynthFrequency), 0.49*float64(s.frequency))
+
+	phase := s.phase
+	if p.FMIndex != 0 && p.FMRatio > 0 {
+		phase += p.FMIndex / (2 * math.Pi) * math.Sin(2*math.Pi*s.modPhase)
+		phase -= math.Floor(phase)
+	}
+
+	var sample float32
+	switch p.Wave {
+	case WaveSquare:
+		duty := min(max(p.Duty+p.DutySweep*t, 0.05), 0.95)
+		sample = -1
+		if phase < duty {
+			sample = 1
+		}
+	case WaveSaw:
+		sample = float32(2*phase - 1)
+	case WaveTriangle:
+		sample = float32(4*math.Abs(phase-0.5) - 1)
+	case WaveNoise:
+		if step := int(phase * noiseSteps); step != s.noiseAt {
+			s.noiseAt = step
+			s.noise = s.nextRandom()
+		}
+		sample = s.noise
+	default:
+		sample = float32(math.Sin(2 * math.Pi * phase))
+	}
+
+	s.phase += f / float64(s.frequency)
+	s.phase -= math.Floor(s.phase)
+	s.modPhase += f * p.FMRatio / float64(s.frequency)
+	s.modPhase -= math.Floor(s.modPhase)
+	s.cursor++
+
+	return sample * p.Envelope.at(t) * p.Gain
+}
+
+func (s *synthSource) channels() []audio.Channel {
+	return audio.ChannelsMono()
+}
+
+func (s *synthSource) read(dst [][]float32) int {
+	written := 0
+
+	for written < len(dst[0]) {
+		if s.cursor >= s.length {
+			if s.loops == 0 || s.length == 0 {
+				break
+			}
+
+			s.restart()
+			if s.loops > 0 {
+				s.loops--
+			}
+			continue
+		}
+
+		dst[0][written] = s.next()
+		written++
+	}
+
+	return written
+}
+
+func (s *synthSource) ended() bool {
+	return s.cursor >= s.length && s.loops == 0
+}
+
+// generates the note up to frame as the oscillators depend on everything before it
+func (s *synthSource) seek(frame int) {
+	frame = max(min(frame, s.length-1), 0)
+	if frame < s.cursor {
+		s.restart()
+	}
+	for s.cursor < frame {
+		s.next()
+	}
+}
+
+func (s *synthSource) setLoop(count int) {
+	s.loops = count
+}
+
+// synthesized notes always loop as a whole
+func (s *synthSource) setLoopRegion(int, int) {}
+
+/*
+BakePatch renders the whole patch at the given frequency into a mono track,
+cache it instead of synthesizing a patch that plays often. SoundBank.AddPatch
+bakes a patch so it can be played by name like a loaded sound.
+*/
+func BakePatch(p Patch, frequency int) audio.Track {
+	s := newSynthSource(p, frequency)
+	out := make([]float32, s.length)
+	s.read([][]float32{out})
+
+	return audio.Track{audio.ChannelsMono()[0]: out}
+}
+
+// synthesizes the patch as it plays, use it for patches changed at runtime
+func PlayPatch(p Patch, cfg PlayConfig) *Voice {
+	return Mixer.playPatch(p, cfg)
+}
+
+func (a *audioMixer) playPatch(p Patch, cfg PlayConfig) *Voice {
+	s := newSynthSource(p, a.outputSpec().Frequency)
+
+	v := newSourceVoice(a, s, cfg)
+	v.stopped.Store(s.length == 0)
+	a.add(v)
+
+	return v
+}
+
+/*
+AddPatch bakes a patch and caches it as a pinned sound under name, after which
+it plays with PlaySound like a loaded file and counts towards instance limits.
+An existing sound with the same name is replaced.
+*/
+func (b *SoundBank) AddPatch(name string, p Patch) {
+	frequency := b.mixer.outputSpec().Frequency
+	spec := audio.Spec{Channels: audio.ChannelsMono(), Frequency: frequency}
+
+	e := newPinnedEntry(newTrackAsset(spec, BakePatch(p, frequency)))
+
+	b.mtx.Lock()
+	b.sounds[name] = e
+	b.mtx.Unlock()
+}
@@ -0,0 +1,208 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mixer
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"goarrg.com/asset/audio"
+)
+
+func TestEnvelope(t *testing.T) {
+	e := Envelope{Attack: 0.1, Decay: 0.2, Sustain: 0.5, Hold: 0.3, Release: 0.4}
+
+	tests := []struct {
+		t    float64
+		gain float32
+	}{
+		{0, 0},
+		{0.05, 0.5},
+		{0.1, 1},
+		{0.2, 0.75},
+		{0.3, 0.5},
+		{0.5, 0.5},
+		{0.8, 0.25},
+		{1, 0},
+		{2, 0},
+	}
+
+	for _, test := range tests {
+		assertNear(t, "envelope", e.at(test.t), test.gain)
+	}
+
+	// without a decay the note holds and releases from full level
+	e = Envelope{Sustain: 0.5, Hold: 0.5, Release: 0.5}
+	assertNear(t, "no decay", e.at(0), 1)
+	assertNear(t, "no decay release", e.at(0.75), 0.5)
+}
+
+func TestPatches(t *testing.T) {
+	patches := map[string]Patch{
+		"coin":      CoinPatch(),
+		"laser":     LaserPatch(),
+		"explosion": ExplosionPatch(),
+		"jump":      JumpPatch(),
+		"blip":      BlipPatch(),
+		"hit":       HitPatch(),
+	}
+
+	for name, p := range patches {
+		samples := BakePatch(p, testFrequency)[audio.ChannelsMono()[0]]
+		if len(samples) == 0 {
+			t.Fatalf("%s baked no samples", name)
+		}
+
+		peak, sum := float32(0), float64(0)
+		for _, s := range samples {
+			peak = max(peak, abs(s))
+			sum += float64(s * s)
+		}
+		rms := math.Sqrt(sum / float64(len(samples)))
+
+		if peak < 0.2 || rms < 0.05 {
+			t.Fatalf("%s peaks at %f with an RMS of %f", name, peak, rms)
+		}
+	}
+}
+
+// returns the samples of a patch held for a second at the test frequency
+func synthesize(p Patch, samples int) []float32 {
+	p.Envelope = Envelope{Sustain: 1, Hold: 1}
+	return BakePatch(p, testFrequency)[audio.ChannelsMono()[0]][:samples]
+}
+
+func TestSynthWaveforms(t *testing.T) {
+	// a period of 100 samples
+	const hz = testFrequency / 100
+
+	for i, s := range synthesize(Patch{Wave: WaveSine, Frequency: hz}, 200) {
+		assertNear(t, "sine", s, float32(math.Sin(2*math.Pi*float64(i)/100)))
+	}
+	for i, s := range synthesize(Patch{Wave: WaveSaw, Frequency: hz}, 200) {
+		assertNear(t, "saw", s, float32(i%100)/50-1)
+	}
+
+	triangle := synthesize(Patch{Wave: WaveTriangle, Frequency: hz}, 100)
+	assertNear(t, "triangle start", triangle[0], 1)
+	assertNear(t, "triangle quarter", triangle[25], 0)
+	assertNear(t, "triangle half", triangle[50], -1)
+
+	square := synthesize(Patch{Wave: WaveSquare, Frequency: hz, Duty: 0.25}, 100)
+	assertNear(t, "square high", square[24], 1)
+	assertNear(t, "square low", square[25], -1)
+
+	noise := synthesize(Patch{Wave: WaveNoise, Frequency: hz}, 200)
+	// held for a sixteenth of the period
+	if noise[0] != noise[6] || noise[0] == noise[7] || noise[0] == noise[100] {
+		t.Fatal("Noise does not follow the pitch")
+	}
+	for _, s := range noise {
+		if s < -1 || s >= 1 {
+			t.Fatalf("Noise sample %f out of range", s)
+		}
+	}
+}
+
+// counts the times the signal crosses zero upwards
+func cycles(signal []float32) int {
+	n := 0
+	for i := 1; i < len(signal); i++ {
+		if signal[i-1] < 0 && signal[i] >= 0 {
+			n++
+		}
+	}
+	return n
+}
+
+func TestSynthSweeps(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch Patch
+		// cycles in the first second
+		want float64
+	}{
+		{"constant", Patch{Frequency: 100}, 100},
+		// the integral of 100 * 2^t over a second
+		{"slide", Patch{Frequency: 100, Slide: 1}, 100 / math.Ln2},
+		{"arpeggio", Patch{Frequency: 100, Arpeggio: 2, ArpeggioDelay: 0.5}, 150},
+		// vibrato averages out over whole periods
+		{"vibrato", Patch{Frequency: 100, VibratoDepth: 1, VibratoRate: 4}, 100},
+	}
+
+	for _, test := range tests {
+		if got := cycles(synthesize(test.patch, testFrequency)); math.Abs(float64(got)-test.want) > 1.5 {
+			t.Fatalf("%s: %d cycles, want %f", test.name, got, test.want)
+		}
+	}
+
+	// FM adds sidebands without changing the pitch's period
+	fm := synthesize(Patch{Frequency: 441, FMRatio: 1, FMIndex: 1}, 300)
+	for i := range 200 {
+		assertNear(t, "fm period", fm[i+100], fm[i])
+	}
+	if plain := synthesize(Patch{Frequency: 441}, 100); fm[10] == plain[10] {
+		t.Fatal("FM did not change the waveform")
+	}
+}
+
+func TestPlayPatch(t *testing.T) {
+	a := newTestMixer()
+	b := NewSoundBank()
+	b.mixer = a
+
+	p := ExplosionPatch()
+	baked := BakePatch(p, testFrequency)[audio.ChannelsMono()[0]]
+	if len(baked) != int(p.Duration()*testFrequency) {
+		t.Fatalf("Baked %d samples, want %d", len(baked), int(p.Duration()*testFrequency))
+	}
+	if !slices.Equal(baked, BakePatch(p, testFrequency)[audio.ChannelsMono()[0]]) {
+		t.Fatal("Baking is not deterministic")
+	}
+
+	// a synthesized voice sounds like the baked sound playing on the other side
+	b.AddPatch("explosion", p)
+	if _, err := b.Play("explosion", PlayConfig{Pan: -1}); err != nil {
+		t.Fatal(err)
+	}
+	v := a.playPatch(p, PlayConfig{Pan: 1})
+
+	for mixed := 0; mixed < len(baked); mixed += 64 {
+		n := min(64, len(baked)-mixed)
+		a.mix(n)
+		for i := range n {
+			assertNear(t, "sample", a.masterTrack[audio.ChannelRight][i], a.masterTrack[audio.ChannelLeft][i])
+		}
+	}
+
+	a.mix(1)
+	if v.Playing() {
+		t.Fatal("Synth voice still playing after its envelope")
+	}
+
+	// looping restarts the note
+	loop := newSynthSource(BlipPatch(), testFrequency)
+	loop.setLoop(1)
+	dst := [][]float32{make([]float32, 3*loop.length)}
+	if n := loop.read(dst); n != 2*loop.length || !loop.ended() {
+		t.Fatalf("Read %d frames of a note of %d looped once", n, loop.length)
+	}
+}
@@ -0,0 +1,272 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mixer
+
+import (
+	"math"
+	"math/rand"
+	"sync/atomic"
+	"time"
+
+	"goarrg.com/asset/audio"
+)
+
+// pass to SetLoop to repeat a voice until it is stopped
+const LoopForever = -1
+
+type PlayConfig struct {
+	Category Category
+	// bus the voice plays on, nil plays on the bus of the category
+	Bus *Bus
+	// linear gain, 0 is treated as 1, use Voice.SetGain to silence a voice
+	Gain float32
+	// stereo position from -1 (left) to 1 (right)
+	Pan float32
+	// plays the voice at a world position heard from the Listener, Pan is ignored
+	Spatial *SpatialConfig
+	// playback rate, 2 plays twice as fast an octave higher, 0 is treated as 1
+	Pitch float32
+	// shifts Pitch by a random amount of up to this many semitones up or down
+	// so repeated sounds like footsteps do not all sound the same
+	PitchVariance float32
+	// mixer sample time the voice starts on, see SampleTime, 0 starts with the next block
+	StartAt int64
+	// once the voice limits are reached a new voice steals the playing voice
+	// with the lowest priority if it is not higher than its own
+	Priority int
+}
+
+/*
+Voice is a handle to a sound being played by the mixer, it stays valid after
+the sound finished but every control becomes a no-op. Controls are applied by
+the audio thread at the start of the next mixed block.
+*/
+type Voice struct {
+	mixer    *audioMixer
+	bus      *Bus
+	priority int
+	// the file the voice plays, used for instance limits
+	sound string
+	// called once by the mixer when it drops the voice
+	release func()
+
+	paused  atomic.Bool
+	stopped atomic.Bool
+
+	// everything below is only touched by the audio thread once the voice was added
+	src      source
+	routes   []channelRoute
+	gain     ramp
+	panLeft  ramp
+	panRight ramp
+	// distance attenuation of spatial voices, 1 otherwise
+	attenuation ramp
+	spatial     *SpatialConfig
+	// the source plays at pitch * doppler, through a rateSource if not 1
+	pitch   float32
+	doppler float32
+	// mixer sample time the voice starts playing at
+	startAt int64
+	fade    fade
+	// counts towards the voice limits, false for music
+	limited bool
+	stolen  bool
+	// order voices were added in
+	seq uint64
+
+	effects     []Effect
+	effectTrack audio.Track
+	meter       meter
+}
+
+func newVoice(a *audioMixer, sample audio.Asset, cfg PlayConfig) *Voice {
+	v := newSourceVoice(a, newAssetSource(sample), cfg)
+	v.stopped.Store(sample.DurationSamples() == 0)
+	return v
+}
+
+func newSourceVoice(a *audioMixer, src source, cfg PlayConfig) *Voice {
+	if cfg.Gain == 0 {
+		cfg.Gain = 1
+	}
+
+	if cfg.Bus == nil {
+		cfg.Bus = a.categoryBus(cfg.Category)
+	}
+
+	left, right := panGains(cfg.Pan)
+	pitch := cfg.Pitch
+	if pitch <= 0 {
+		pitch = 1
+	}
+
+	// spatial voices are placed relative to the listener once they are added
+	var spatial *SpatialConfig
+	if cfg.Spatial != nil {
+		c := *cfg.Spatial
+		c.defaults()
+		spatial = &c
+	}
+
+	if cfg.PitchVariance > 0 {
+		a.mtx.Lock()
+		pitch *= semitones(float32(a.random()*2-1) * cfg.PitchVariance)
+		a.mtx.Unlock()
+	}
+
+	if pitch != 1 || (spatial != nil && spatial.SpeedOfSound > 0) {
+		r := newRateSource(src)
+		r.setRate(pitch, 0)
+		src = r
+	}
+
+	return &Voice{
+		mixer:    a,
+		bus:      cfg.Bus,
+		priority: cfg.Priority,
+		src:      src,
+		routes:   channelRoutes(src.channels(), a.outputSpec().Channels),
+		gain:     newRamp(max(cfg.Gain, 0)),
+		panLeft:  newRamp(left),
+		panRight: newRamp(right),
+		fade:     fade{from: 1, to: 1},
+		startAt:  cfg.StartAt,
+
+		attenuation: newRamp(1),
+		spatial:     spatial,
+		pitch:       pitch,
+		doppler:     1,
+	}
+}
+
+// returns the playback rate that shifts the pitch by the given semitones
+func semitones(n float32) float32 {
+	return float32(math.Exp2(float64(n) / 12))
+}
+
+// returns a random number in [0, 1), must be called with mtx held
+func (a *audioMixer) random() float64 {
+	if a.rand == nil {
+		a.rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return a.rand.Float64()
+}
+
+// stops the voice, it cannot be resumed afterwards
+func (v *Voice) Stop() {
+	v.stopped.Store(true)
+}
+
+func (v *Voice) Pause() {
+	v.paused.Store(true)
+}
+
+func (v *Voice) Resume() {
+	v.paused.Store(false)
+}
+
+// sets how many more times the voice restarts after reaching the end, 0 plays
+// it to the end once and LoopForever repeats it until stopped
+func (v *Voice) SetLoop(count int) {
+	v.mixer.commands.push(func() {
+		v.src.setLoop(max(count, LoopForever))
+	})
+}
+
+// moves playback to the given sample of the sound at the output frequency,
+// out of range values are clamped
+func (v *Voice) Seek(sample int) {
+	v.mixer.commands.push(func() {
+		v.src.seek(sample)
+	})
+}
+
+// sets the linear gain of the voice, changes are ramped to avoid clicks
+func (v *Voice) SetGain(gain float32) {
+	v.mixer.commands.push(func() {
+		v.gain.set(max(gain, 0), v.mixer.rampSamples)
+	})
+}
+
+/*
+SetPitch sets the playback rate, 2 plays twice as fast an octave higher and
+0.5 half as fast an octave lower. The rate glides to the new value so it can
+be changed every frame, for example to follow an engine's RPM.
+*/
+func (v *Voice) SetPitch(rate float32) {
+	v.mixer.commands.push(func() {
+		v.pitch = min(max(rate, minRate), maxRate)
+
+		r, ok := v.src.(*rateSource)
+		if !ok {
+			if v.pitch*v.doppler == 1 {
+				return
+			}
+
+			r = newRateSource(v.src)
+			v.src = r
+		}
+
+		r.setRate(v.pitch*v.doppler, v.mixer.rampSamples)
+	})
+}
+
+// sets the stereo position from -1 (left) to 1 (right) using a constant power
+// pan law, changes are ramped to avoid clicks. Spatial voices ignore it.
+func (v *Voice) SetPan(pan float32) {
+	left, right := panGains(pan)
+
+	v.mixer.commands.push(func() {
+		v.panLeft.set(left, v.mixer.rampSamples)
+		v.panRight.set(right, v.mixer.rampSamples)
+	})
+}
+
+// fades the voice to gain over the given seconds starting with the next mixed
+// sample, the fade is applied on top of SetGain
+func (v *Voice) FadeTo(gain float32, seconds float64, curve Curve) {
+	v.mixer.commands.push(func() {
+		v.fadeAt(v.mixer.clock, gain, v.mixer.seconds(seconds), curve, false)
+	})
+}
+
+// fades the voice to silence over the given seconds then stops it
+func (v *Voice) FadeOut(seconds float64, curve Curve) {
+	v.mixer.commands.push(func() {
+		v.fadeAt(v.mixer.clock, 0, v.mixer.seconds(seconds), curve, true)
+	})
+}
+
+// starts a fade at mixer sample time t, only called by the audio thread once the voice was added
+func (v *Voice) fadeAt(t int64, gain float32, samples int, curve Curve, stop bool) {
+	v.fade = fade{
+		start:  t,
+		length: samples,
+		from:   v.fade.at(t),
+		to:     max(gain, 0),
+		curve:  curve,
+		stop:   stop,
+	}
+}
+
+// returns true until the voice finished or was stopped, paused voices are not playing
+func (v *Voice) Playing() bool {
+	return !v.stopped.Load() && !v.paused.Load()
+}
@@ -0,0 +1,272 @@
+//go:build !goarrg_disable_gl
+// +build !goarrg_disable_gl
+
+/*
+Copyright 2026 The goARRG Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mixer
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+
+	"goarrg.com/asset/audio"
+	"goarrg.com/debug"
+)
+
+const (
+	wavFormatPCM        = 0x0001
+	wavFormatFloat      = 0x0003
+	wavFormatExtensible = 0xFFFE
+	// size of a WAVE_FORMAT_EXTENSIBLE fmt chunk, the largest one decoded
+	wavMaxFmtSize = 40
+)
+
+/*
+wavDecoder decodes a WAV file in chunks so it can be streamed instead of being
+decoded all at once, it supports integer PCM of 8 to 32 bits and float PCM.
+*/
+type wavDecoder struct {
+	r    io.ReaderAt
+	spec audio.Spec
+	// WAVE_FORMAT_PCM or WAVE_FORMAT_IEEE_FLOAT
+	format     uint16
+	frameBytes int
+	dataStart  int64
+	frames     int
+	cursor     int
+	buf        []byte
+}
+
+func newWAVDecoder(r io.ReaderAt, size int64) (*wavDecoder, error) {
+	header := make([]byte, 12)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return nil, debug.ErrorWrapf(err, "Failed to decode WAV")
+	}
+	if string(header[0:4]) != "RIFF" || string(header[8:12]) != "WAVE" {
+		return nil, debug.Errorf("Failed to decode WAV, not a RIFF WAVE file")
+	}
+
+	d := &wavDecoder{r: r}
+	bits := 0
+	foundFormat := false
+
+	chunk := make([]byte, 8)
+	for offset := int64(12); offset+8 <= size; {
+		if _, err := r.ReadAt(chunk, offset); err != nil {
+			return nil, debug.ErrorWrapf(err, "Failed to decode WAV")
+		}
+
+		id := string(chunk[0:4])
+		chunkSize := int64(binary.LittleEndian.Uint32(chunk[4:8]))
+		offset += 8
+
+		switch id {
+		case "fmt ":
+			if chunkSize < 16 {
+				return nil, debug.Errorf("Failed to decode WAV, fmt chunk too small")
+			}
+
+			// only the extensible format's fields are read, anything after them is skipped
+			body := make([]byte, min(chunkSize, wavMaxFmtSize))
+			if _, err := r.ReadAt(body, offset); err != nil {
+				return nil, debug.ErrorWrapf(err, "Failed to decode WAV")
+			}
+
+			d.format = binary.LittleEndian.Uint16(body[0:2])
+			channels := int(binary.LittleEndian.Uint16(body[2:4]))
+			d.spec.Frequency = int(binary.LittleEndian.Uint32(body[4:8]))
+			bits = int(binary.LittleEndian.Uint16(body[14:16]))
+
+			// the real format is the first 2 bytes of the sub format GUID
+			if d.format == wavFormatExtensible && len(body) >= 26 {
+				d.format = binary.LittleEndian.Uint16(body[24:26])
+			}
+
+			switch channels {
+			case 1:
+				d.spec.Channels = audio.ChannelsMono()
+			case 2:
+				d.spec.Channels = audio.ChannelsStereo()
+			case 6:
+				d.spec.Channels = audio.Channels5Point1()
+			case 8:
+				d.spec.Channels = audio.Channels7Point1()
+			default:
+				return nil, debug.Errorf("Failed to decode WAV, unsupported channel count %d", channels)
+			}
+
+			d.frameBytes = channels * bits / 8
+			if d.frameBytes == 0 {
+				return nil, debug.Errorf("Failed to decode WAV, unsupported %d bits per sample", bits)
+			}
+			foundFormat = true
+
+		case "data":
+			if !foundFormat {
+				return nil, debug.Errorf("Failed to decode WAV, data chunk before fmt chunk")
+			}
+
+			d.dataStart = offset
+			d.frames = int(min(chunkSize, size-offset)) / d.frameBytes
+		}
+
+		if d.dataStart != 0 {
+			break
+		}
+
+		// chunks are padded to an even size
+		offset += chunkSize + chunkSize&1
+	}
+
+	if !foundFormat || d.dataStart == 0 {
+		return nil, debug.Errorf("Failed to decode WAV, missing fmt or data chunk")
+	}
+
+	switch {
+	case d.format == wavFormatPCM && (bits == 8 || bits == 16 || bits == 24 || bits == 32):
+	case d.format == wavFormatFloat && (bits == 32 || bits == 64):
+	default:
+		return nil, debug.Errorf("Failed to decode WAV, unsupported format %#x with %d bits", d.format, bits)
+	}
+
+	return d, nil
+}
+
+// decodes the next frames into dst, returns 0 once the end was reached
+func (d *wavDecoder) read(dst [][]float32) (int, error) {
+	frames := min(len(dst[0]), d.frames-d.cursor)
+	if frames <= 0 {
+		return 0, nil
+	}
+
+	size := frames * d.frameBytes
+	if cap(d.buf) < size {
+		d.buf = make([]byte, size)
+	}
+	buf := d.buf[:size]
+
+	if _, err := d.r.ReadAt(buf, d.dataStart+int64(d.cursor*d.frameBytes)); err != nil && err != io.EOF {
+		return 0, debug.ErrorWrapf(err, "Failed to decode WAV")
+	}
+
+	channels := len(d.spec.Channels)
+	sampleBytes := d.frameBytes / channels
+
+	for i := 0; i < frames; i++ {
+		for c := 0; c < channels; c++ {
+			s := buf[(i*channels+c)*sampleBytes:]
+			dst[c][i] = d.decodeSample(s, sampleBytes)
+		}
+	}
+
+	d.cursor += frames
+	return frames, nil
+}
+
+func (d *wavDecoder) decodeSample(s []byte, size int) float32 {
+	if d.format == wavFormatFloat {
+		if size == 8 {
+			return float32(math.Float64frombits(binary.LittleEndian.Uint64(s)))
+		}
+		return math.Float32frombits(binary.LittleEndian.Uint32(s))
+	}
+
+	switch size {
+	case 1:
+		// 8 bit PCM is the only unsigned one
+		return float32(int(s[0])-128) / 128
+	case 2:
+		return float32(int16(binary.LittleEndian.Uint16(s))) / (1 << 15)
+	case 3:
+		return float32(int32(uint32(s[0])<<8|uint32(s[1])<<16|uint32(s[2])<<24)>>8) / (1 << 23)
+	default:
+		return float32(float64(int32(binary.LittleEndian.Uint32(s))) / (1 << 31))
+	}
+}
+
+func (d *wavDecoder) seek(frame int) {
+	d.cursor = max(min(frame, d.frames), 0)
+}
+
+// channel masks of WAVE_FORMAT_EXTENSIBLE for the layouts with more than 2 channels
+var wavChannelMasks = map[int]uint32{
+	6: 0x3F,
+	8: 0x63F,
+}
+
+/*
+writeWAV encodes the first samples of every channel of track as a 32 bit float
+WAV file, layouts with more than 2 channels use WAVE_FORMAT_EXTENSIBLE.
+*/
+func writeWAV(w io.Writer, spec audio.Spec, track audio.Track, samples int) error {
+	channels := len(spec.Channels)
+	frameBytes := channels * 4
+	dataBytes := samples * frameBytes
+
+	fmtBytes := 16
+	if channels > 2 {
+		fmtBytes = 40
+	}
+
+	header := make([]byte, 0, 20+fmtBytes+8)
+	header = append(header, "RIFF"...)
+	header = binary.LittleEndian.AppendUint32(header, uint32(4+8+fmtBytes+8+dataBytes))
+	header = append(header, "WAVEfmt "...)
+	header = binary.LittleEndian.AppendUint32(header, uint32(fmtBytes))
+
+	format := uint16(wavFormatFloat)
+	if channels > 2 {
+		format = wavFormatExtensible
+	}
+
+	header = binary.LittleEndian.AppendUint16(header, format)
+	header = binary.LittleEndian.AppendUint16(header, uint16(channels))
+	header = binary.LittleEndian.AppendUint32(header, uint32(spec.Frequency))
+	header = binary.LittleEndian.AppendUint32(header, uint32(spec.Frequency*frameBytes))
+	header = binary.LittleEndian.AppendUint16(header, uint16(frameBytes))
+	header = binary.LittleEndian.AppendUint16(header, 32)
+
+	if channels > 2 {
+		header = binary.LittleEndian.AppendUint16(header, 22)
+		header = binary.LittleEndian.AppendUint16(header, 32)
+		header = binary.LittleEndian.AppendUint32(header, wavChannelMasks[channels])
+		// KSDATAFORMAT_SUBTYPE_IEEE_FLOAT
+		header = binary.LittleEndian.AppendUint16(header, wavFormatFloat)
+		header = append(header, 0x00, 0x00, 0x00, 0x00, 0x10, 0x00, 0x80, 0x00, 0x00, 0xAA, 0x00, 0x38, 0x9B, 0x71)
+	}
+
+	header = append(header, "data"...)
+	header = binary.LittleEndian.AppendUint32(header, uint32(dataBytes))
+
+	if _, err := w.Write(header); err != nil {
+		return debug.ErrorWrapf(err, "Failed to encode WAV")
+	}
+
+	data := make([]byte, 0, dataBytes)
+	for i := 0; i < samples; i++ {
+		for _, c := range spec.Channels {
+			data = binary.LittleEndian.AppendUint32(data, math.Float32bits(track[c][i]))
+		}
+	}
+
+	if _, err := w.Write(data); err != nil {
+		return debug.ErrorWrapf(err, "Failed to encode WAV")
+	}
+
+	return nil
+}